@@ -1,69 +1,380 @@
 package main
 
 import (
+	"context"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/devbrain/gateway/internal/cache"
 	"github.com/devbrain/gateway/internal/config"
+	"github.com/devbrain/gateway/internal/embedding"
 	"github.com/devbrain/gateway/internal/handler"
 	"github.com/devbrain/gateway/internal/middleware"
+	"github.com/devbrain/gateway/internal/version"
+	"golang.org/x/net/netutil"
 )
 
+// defaultMemoryCacheSize는 CACHE_MAX_ENTRIES 미설정 시 인메모리 캐시 최대 항목 수
+const defaultMemoryCacheSize = 10000
+
 func main() {
 	log.Println(strings.Repeat("=", 50))
-	log.Println("🚀 DevBrain Gateway 시작")
+	log.Printf("🚀 DevBrain Gateway 시작 (%s, commit %s, built %s)", version.Version, version.Commit, version.BuildTime)
 	log.Println(strings.Repeat("=", 50))
 
 	// 설정 로드
 	cfg := config.Load()
-	log.Printf("📋 설정 로드 완료: Backend=%s, Redis=%s", cfg.BackendURL, cfg.RedisAddr)
+	log.Printf("📋 설정 로드 완료: Backend=%s, Cache=%s, Redis=%s", cfg.BackendURL, cfg.CacheBackend, cfg.RedisAddr)
+
+	// 캐시 저장소 초기화 (CACHE_BACKEND)
+	var store cache.Cache
+	var redisClient *cache.RedisClient // Redis 전용 기능(LRU 제거, 시맨틱 캐시)에 사용
+	switch cfg.CacheBackend {
+	case "redis":
+		redisClient = cache.NewRedisClient(cfg.RedisAddr, cfg.RedisPassword,
+			cfg.RedisMaxRetries, time.Duration(cfg.RedisRetryBackoffMs)*time.Millisecond)
+		store = redisClient
+		// 백그라운드 연결 확인 (Redis 재시작 후 캐시 사용을 바로 재개, 끊김/재연결은 로그와 /api/cache/stats에 기록)
+		if cfg.RedisHealthInterval > 0 {
+			redisClient.StartHealthCheck(context.Background(), time.Duration(cfg.RedisHealthInterval)*time.Second)
+		}
+	case "memory":
+		capacity := cfg.CacheMaxEntries
+		if capacity <= 0 {
+			capacity = defaultMemoryCacheSize
+		}
+		store = cache.NewMemoryCache(capacity)
+		log.Printf("🧪 인메모리 캐시 사용: 최대 %d개 (인스턴스 간 공유 안 됨)", capacity)
+	default:
+		log.Fatalf("❌ 알 수 없는 CACHE_BACKEND: %s", cfg.CacheBackend)
+	}
+	defer store.Close()
+	if staler, ok := store.(cache.StaleReader); ok {
+		staler.SetStaleGrace(time.Duration(cfg.StaleGrace) * time.Second)
+	}
+
+	// 캐시 통계의 항목 크기 집계 (Redis 전용)
+	switch {
+	case cfg.CacheSizeStats == cache.SizeStatsOff:
+	case cfg.CacheSizeStats != cache.SizeStatsSample && cfg.CacheSizeStats != cache.SizeStatsFull:
+		log.Fatalf("❌ 알 수 없는 CACHE_SIZE_STATS: %s", cfg.CacheSizeStats)
+	case cfg.CacheSizeStats == cache.SizeStatsSample && cfg.CacheSizeSample < 1:
+		log.Fatalf("❌ CACHE_SIZE_SAMPLE은 1 이상이어야 합니다: %d", cfg.CacheSizeSample)
+	case redisClient == nil:
+		log.Println("⚠️ 캐시 항목 크기 집계는 Redis 캐시에서만 지원 (CACHE_BACKEND=redis)")
+	default:
+		redisClient.SetSizeStats(cfg.CacheSizeStats, cfg.CacheSizeSample)
+		log.Printf("📏 캐시 항목 크기 집계: %s", cfg.CacheSizeStats)
+	}
+
+	// 채팅 캐시 항목 저장 형식 (Redis 전용, 기존 형식으로 저장된 항목도 계속 읽음)
+	serializer, err := cache.NewSerializer(cfg.CacheSerializer)
+	if err != nil {
+		log.Fatalf("❌ CACHE_SERIALIZER 설정 오류: %v", err)
+	}
+	if redisClient != nil && serializer.Name() != cache.SerializerJSON {
+		redisClient.SetSerializer(serializer)
+		log.Printf("📦 캐시 항목 저장 형식: %s", serializer.Name())
+	}
+
+	// 채팅 캐시 키 버킷 (Redis 전용, 통계/일괄 작업의 SCAN을 버킷별로 나눠 동시에 실행)
+	if cfg.CacheShards > 1 {
+		if redisClient == nil {
+			log.Println("⚠️ 캐시 키 버킷은 Redis 캐시에서만 지원 (CACHE_BACKEND=redis)")
+		} else {
+			redisClient.SetShards(cfg.CacheShards)
+			log.Printf("🗂️ 캐시 키 버킷: %d개", cfg.CacheShards)
+		}
+	}
 
-	// Redis 클라이언트 초기화
-	redisClient := cache.NewRedisClient(cfg.RedisAddr, cfg.RedisPassword)
-	defer redisClient.Close()
+	// write-behind 캐시 저장 (Redis 전용, 응답 경로에서 SET 왕복 제거, 종료 시 store.Close에서 남은 항목 기록)
+	if cfg.CacheWriteBehind {
+		switch {
+		case cfg.CacheWriteQueueFull != cache.WriteQueueDrop && cfg.CacheWriteQueueFull != cache.WriteQueueBlock:
+			log.Fatalf("❌ 알 수 없는 CACHE_WRITE_QUEUE_FULL: %s", cfg.CacheWriteQueueFull)
+		case redisClient == nil:
+			log.Println("⚠️ write-behind 캐시 저장은 Redis 캐시에서만 지원 (CACHE_BACKEND=redis)")
+		default:
+			redisClient.EnableWriteBehind(cfg.CacheWriteQueue, cfg.CacheWriteBatch,
+				time.Duration(cfg.CacheWriteFlushMs)*time.Millisecond, cfg.CacheWriteQueueFull)
+			log.Printf("📮 write-behind 캐시 저장: 큐 %d개, 배치 %d개, %dms 주기 (가득 차면 %s)",
+				cfg.CacheWriteQueue, cfg.CacheWriteBatch, cfg.CacheWriteFlushMs, cfg.CacheWriteQueueFull)
+		}
+	}
+
+	// LRU 캐시 제거 (Redis maxmemory 정책과 별개로 항목 수 상한 유지)
+	if redisClient != nil && cfg.CacheMaxEntries > 0 {
+		maxAge := time.Duration(cfg.CacheTTL+cfg.StaleGrace) * time.Second
+		redisClient.StartEviction(context.Background(), cfg.CacheMaxEntries, time.Duration(cfg.CacheEvictionInterval)*time.Second, maxAge)
+		log.Printf("🧹 LRU 캐시 제거 활성화: 최대 %d개", cfg.CacheMaxEntries)
+	}
 
 	// 핸들러 생성
-	proxyHandler := handler.NewProxyHandler(cfg.BackendURL, redisClient, cfg)
+	proxyHandler := handler.NewProxyHandler(cfg.BackendURL, store, cfg)
+
+	// 클라이언트 IP 결정 (IP 필터, Rate Limit, Backend 전달 헤더에서 공통 사용)
+	trusted, err := middleware.ParseCIDRs(cfg.TrustedProxies)
+	if err != nil {
+		log.Fatalf("❌ TRUSTED_PROXIES 파싱 실패: %v", err)
+	}
+	clientIPs := middleware.NewClientIPResolver(trusted)
+	proxyHandler.SetClientIPResolver(clientIPs)
+
+	// 캐시 키 버전 (바꾸면 Redis를 비우지 않고 기존 채팅 캐시 전체를 무효화)
+	switch cfg.CacheVersion {
+	case "":
+	case "backend":
+		proxyHandler.SyncCacheVersion(context.Background(), time.Duration(cfg.CacheVersionRefresh)*time.Second)
+	default:
+		versioner, ok := store.(cache.KeyVersioner)
+		if !ok {
+			log.Fatalf("❌ CACHE_VERSION은 캐시 키 버전을 지원하는 캐시 구현이 필요합니다 (CACHE_BACKEND=%s)", cfg.CacheBackend)
+		}
+		versioner.SetKeyVersion(cfg.CacheVersion)
+		log.Printf("🏷️ 캐시 키 버전: %s", cfg.CacheVersion)
+	}
+
+	// 테넌트별 캐시 키 공간 (멀티 테넌트 환경에서 다른 테넌트의 답변 재사용 방지)
+	switch cfg.CacheTenant {
+	case "":
+	case "header":
+		log.Printf("🏢 테넌트별 캐시 키: %s 헤더 (신뢰하는 상위 프록시가 설정해야 함)", cfg.CacheTenantHeader)
+	case "api_key":
+		log.Printf("🏢 테넌트별 캐시 키: 인증된 API 키")
+	default:
+		log.Fatalf("❌ CACHE_TENANT 설정 오류: %q (header, api_key)", cfg.CacheTenant)
+	}
+
+	// 캐시 신선도 검사 (캐시 히트 일부를 백그라운드에서 다시 질의)
+	if cfg.CacheRefreshProbability < 0 || cfg.CacheRefreshProbability > 1 {
+		log.Fatalf("❌ CACHE_REFRESH_PROBABILITY 설정 오류: %g (0.0-1.0)", cfg.CacheRefreshProbability)
+	}
+	if cfg.CacheRefreshProbability > 0 {
+		log.Printf("🔍 캐시 신선도 검사: 히트의 %g%% (최소 유사도 %.2f)", cfg.CacheRefreshProbability*100, cfg.CacheRefreshMinSimilarity)
+	}
+
+	// 시맨틱 캐시 (EMBEDDING_PROVIDER 설정 시)
+	var embedder embedding.Embedder
+	switch cfg.EmbeddingProvider {
+	case "":
+	case "http":
+		embedder = embedding.NewHTTPEmbedder(cfg.EmbeddingURL, cfg.EmbeddingModel, cfg.EmbeddingAPIKey, time.Duration(cfg.EmbeddingTimeout)*time.Second)
+		log.Printf("🧠 시맨틱 캐시 활성화: %s (%s)", cfg.EmbeddingModel, cfg.EmbeddingURL)
+	case "fake":
+		embedder = embedding.FakeEmbedder{}
+		log.Println("🧠 시맨틱 캐시 활성화: fake embedder")
+	default:
+		log.Fatalf("❌ 알 수 없는 EMBEDDING_PROVIDER: %s", cfg.EmbeddingProvider)
+	}
+	if embedder != nil && redisClient == nil {
+		log.Println("⚠️ 시맨틱 캐시는 Redis 캐시에서만 지원 (CACHE_BACKEND=redis)")
+	} else if embedder != nil {
+		semanticCache := cache.NewSemanticCache(redisClient, embedder, cfg.SimilarityThreshold)
+		if cfg.SemanticVectorIndex {
+			if err := semanticCache.EnableVectorIndex(context.Background()); err != nil {
+				log.Printf("⚠️ 벡터 인덱스 사용 불가, 전수 비교로 대체: %v", err)
+			} else {
+				log.Println("🧭 RediSearch 벡터 인덱스 사용")
+			}
+		}
+		proxyHandler.SetSemanticCache(semanticCache)
+	}
+
+	// 브라우저용 HTML 에러 페이지 템플릿 (API 클라이언트는 계속 JSON)
+	if cfg.ErrorPageTemplate != "" {
+		if err := middleware.LoadErrorTemplate(cfg.ErrorPageTemplate); err != nil {
+			log.Fatalf("❌ 에러 페이지 템플릿 로드 실패: %v", err)
+		}
+		log.Printf("🧾 에러 페이지 템플릿: %s", cfg.ErrorPageTemplate)
+	}
 
 	// 미들웨어 체인 구성
 	var h http.Handler = proxyHandler
 
+	// 전역 타임아웃 (응답을 버퍼링하므로 SSE/grpc-web 스트리밍 경로와 캐시 백업/복원은 제외)
+	if cfg.GlobalTimeout > 0 {
+		bypass := append(slices.Clone(cfg.GlobalTimeoutBypass), "/admin/cache/")
+		if cfg.GRPCPathPrefix != "" {
+			bypass = append(bypass, cfg.GRPCPathPrefix)
+		}
+		h = middleware.NewTimeout(time.Duration(cfg.GlobalTimeout)*time.Second, bypass).Middleware(h)
+		log.Printf("⏱️ 전역 요청 타임아웃: %d초 (제외: %s)", cfg.GlobalTimeout, strings.Join(bypass, ", "))
+	}
+
 	// Rate Limiter 적용
 	rateLimiter := middleware.NewRateLimiter(cfg.RateLimit, cfg.RateBurst)
+	rateLimiter.SetAuthenticatedTier(cfg.RateLimitAuth, cfg.RateBurstAuth)
+	rateLimiter.SetMaxClients(cfg.RateLimitMaxClients)
+	rateLimiter.SetClientIPResolver(clientIPs)
+	if len(cfg.IPBurstOverrides) > 0 {
+		if err := rateLimiter.SetIPOverrides(cfg.IPBurstOverrides); err != nil {
+			log.Fatalf("❌ IP_BURST_OVERRIDES 설정 오류: %v", err)
+		}
+		log.Printf("🎚️ IP별 Rate Limit 재정의: %d개", len(cfg.IPBurstOverrides))
+	}
+	if cfg.RateLimitWarmup > 0 {
+		rateLimiter.SetWarmup(time.Duration(cfg.RateLimitWarmup)*time.Second, cfg.RateLimitWarmupMultiplier)
+		log.Printf("🌅 Rate Limit warmup: %d초 (버스트 %.1f배에서 감소)", cfg.RateLimitWarmup, cfg.RateLimitWarmupMultiplier)
+	}
+	rateLimiter.SetNearLimitWarning(cfg.RateLimitWarnFraction, time.Duration(cfg.RateLimitWarnInterval)*time.Second)
+	rateLimiter.SetQuotaHeaders(cfg.RateLimitHeaders)
+	if cfg.RateLimitWait && cfg.RateLimitMaxWait > 0 {
+		rateLimiter.SetWaitMode(time.Duration(cfg.RateLimitMaxWait) * time.Millisecond)
+		log.Printf("⏳ Rate Limit 대기 모드: 최대 %dms", cfg.RateLimitMaxWait)
+	}
+	if cfg.AdaptiveRateLimit {
+		rateLimiter.SetHealthSource(proxyHandler)
+		log.Printf("🩺 적응형 Rate Limiting: 에러율 %.2f / 지연 %dms 초과 시 한도 축소 (최소 %.2f배)",
+			cfg.AdaptiveErrorThreshold, cfg.AdaptiveLatencyThreshold, cfg.AdaptiveMinFactor)
+	}
+	if cfg.RateLimitCacheFallback {
+		rateLimiter.SetCacheFallback(true)
+		log.Println("💾 Rate Limit 초과 시 캐시 fallback 활성화")
+	}
+	proxyHandler.SetRateLimiter(rateLimiter)
+
+	// API 키 인증 후 Rate Limiter: 잘못된 키로 보내는 요청도 401 전에 IP 한도를 받도록 Rate Limiter를 바깥에 두고,
+	// 유효한 키는 Rate Limiter가 직접 확인해 키 등급을 적용
+	auth := middleware.NewAuth(cfg.APIKeyHeader, cfg.APIKeys)
+	rateLimiter.SetIdentifier(auth.Identify)
+	h = auth.Middleware(h)
 	h = rateLimiter.Middleware(h)
 
-	// 로깅 미들웨어
-	h = middleware.LoggingMiddleware(h)
+	// 로깅 미들웨어 (ACCESS_LOG_FILE 지정 시 파일로 기록)
+	var accessLog *log.Logger
+	if cfg.AccessLogFile != "" {
+		logFile, err := middleware.NewRotatingFile(cfg.AccessLogFile, cfg.AccessLogMaxSize, cfg.AccessLogMaxBackups)
+		if err != nil {
+			log.Fatalf("❌ 접근 로그 파일 열기 실패: %v", err)
+		}
+		defer logFile.Close()
 
-	// CORS 미들웨어
-	h = middleware.CORSMiddleware(h)
+		accessLog = log.New(logFile, "", log.LstdFlags)
+		log.Printf("📝 접근 로그 파일: %s", cfg.AccessLogFile)
+	}
+	accessLogger := middleware.NewAccessLogger(accessLog)
+	if err := accessLogger.SetFormat(cfg.LogFormat); err != nil {
+		log.Fatalf("❌ LOG_FORMAT 설정 오류: %v", err)
+	}
+	if cfg.LogSampleRate > 0 {
+		secretHeaders := []string{cfg.APIKeyHeader, cfg.BackendAPIKeyHeader}
+		if err := accessLogger.SetSampling(cfg.LogSampleRate, cfg.LogSampleMaxBody, secretHeaders, proxyHandler.RedactText); err != nil {
+			log.Fatalf("❌ LOG_SAMPLE_RATE 설정 오류: %v", err)
+		}
+		log.Printf("🔬 접근 로그 샘플링: 요청의 %.1f%%는 헤더/바디 포함 (바디 최대 %d바이트)", cfg.LogSampleRate*100, cfg.LogSampleMaxBody)
+	}
+	if len(cfg.LogExcludePaths) > 0 {
+		accessLogger.SetExcludePaths(cfg.LogExcludePaths)
+		log.Printf("📝 접근 로그 제외 경로: %v", cfg.LogExcludePaths)
+	}
+	h = accessLogger.Middleware(h)
+
+	// 요청 처리 시간 히스토그램 (GET /metrics)
+	if cfg.MetricsEnabled {
+		buckets, err := middleware.ParseLatencyBuckets(cfg.LatencyBuckets)
+		if err != nil {
+			log.Fatalf("❌ LATENCY_BUCKETS 파싱 실패: %v", err)
+		}
+		latency := middleware.NewLatencyHistogram(buckets)
+		h = latency.Middleware(h)
+		proxyHandler.SetLatencyMetrics(latency)
+		log.Printf("📊 요청 처리 시간 지표 활성화 (GET /metrics): 버킷 %v초", buckets)
+	}
+
+	// CORS 미들웨어 (경로별 규칙 지원)
+	defaultCORS := middleware.CORSRule{
+		AllowedOrigins: cfg.CORSAllowedOrigins,
+		AllowedMethods: cfg.CORSAllowedMethods,
+		AllowedHeaders: cfg.CORSAllowedHeaders,
+	}
+	corsRules, err := middleware.ParseCORSRules(cfg.CORSPathRules, defaultCORS)
+	if err != nil {
+		log.Fatalf("❌ CORS 규칙 파싱 실패: %v", err)
+	}
+	cors := middleware.NewCORS(defaultCORS, corsRules)
+	if cfg.CORSStrictPreflight {
+		// 존재하지 않는 라우트의 OPTIONS는 일반 라우팅으로 넘겨 404/405가 드러나도록 함
+		cors.SetRouteMatcher(proxyHandler.HasRoute)
+	}
+	h = cors.Middleware(h)
+
+	// IP 허용/차단 목록 (가장 바깥에서 거부)
+	if len(cfg.IPAllowlist) > 0 || len(cfg.IPDenylist) > 0 {
+		allow, err := middleware.ParseCIDRs(cfg.IPAllowlist)
+		if err != nil {
+			log.Fatalf("❌ IP_ALLOWLIST 파싱 실패: %v", err)
+		}
+		deny, err := middleware.ParseCIDRs(cfg.IPDenylist)
+		if err != nil {
+			log.Fatalf("❌ IP_DENYLIST 파싱 실패: %v", err)
+		}
+
+		defaultAllow := len(allow) == 0
+		switch cfg.IPFilterDefault {
+		case "allow":
+			defaultAllow = true
+		case "deny":
+			defaultAllow = false
+		}
+
+		h = middleware.NewIPFilter(allow, deny, defaultAllow, clientIPs).Middleware(h)
+		log.Printf("🛡️ IP 필터 활성화: 허용 %d개, 차단 %d개, 기본 허용=%t", len(allow), len(deny), defaultAllow)
+	}
 
 	// 서버 시작
+	// 느린 클라이언트(slowloris)가 연결을 붙잡지 않도록 타임아웃 설정
+	// SSE 스트림은 쓰기 데드라인을 해제하므로 SERVER_WRITE_TIMEOUT의 영향을 받지 않음
 	server := &http.Server{
-		Addr:    ":" + cfg.Port,
-		Handler: h,
+		Addr:              ":" + cfg.Port,
+		Handler:           h,
+		ReadHeaderTimeout: time.Duration(cfg.ServerReadHeaderTimeout) * time.Second,
+		ReadTimeout:       time.Duration(cfg.ServerReadTimeout) * time.Second,
+		WriteTimeout:      time.Duration(cfg.ServerWriteTimeout) * time.Second,
+		IdleTimeout:       time.Duration(cfg.ServerIdleTimeout) * time.Second,
 	}
 
-	// Graceful shutdown
+	// Graceful shutdown: 새 연결을 받지 않고 진행 중인 요청(SSE 스트림 포함)이 끝나기를 SHUTDOWN_TIMEOUT까지 기다린 뒤,
+	// 그래도 남은 연결은 끊음
+	shutdownDone := make(chan struct{})
 	go func() {
+		defer close(shutdownDone)
 		sigChan := make(chan os.Signal, 1)
 		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 		<-sigChan
 
-		log.Println("🛑 서버 종료 중...")
-		server.Close()
+		log.Printf("🛑 서버 종료 중... (진행 중인 요청 최대 %d초 대기)", cfg.ShutdownTimeout)
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.ShutdownTimeout)*time.Second)
+		defer cancel()
+		if err := server.Shutdown(ctx); err != nil {
+			log.Printf("⚠️ 종료 대기 시간 초과, 남은 연결 종료: %v", err)
+			server.Close()
+		}
 	}()
 
+	listener, err := net.Listen("tcp", server.Addr)
+	if err != nil {
+		log.Fatalf("❌ 포트 바인딩 실패: %v", err)
+	}
+
+	// 동시 TCP 연결 수 제한 (한도 도달 시 새 연결은 Accept 대기)
+	if cfg.MaxConnections > 0 {
+		listener = netutil.LimitListener(listener, cfg.MaxConnections)
+		log.Printf("🔒 최대 동시 연결 수: %d", cfg.MaxConnections)
+	}
+
 	log.Printf("✅ Gateway 서버 시작: http://localhost:%s", cfg.Port)
-	if err := server.ListenAndServe(); err != http.ErrServerClosed {
+	if err := server.Serve(listener); err != http.ErrServerClosed {
 		log.Fatalf("❌ 서버 오류: %v", err)
 	}
+	// Serve는 Shutdown 호출 즉시 반환하므로 진행 중인 요청이 끝날 때까지 대기 (이후 캐시 연결 정리)
+	<-shutdownDone
 
 	log.Println("👋 서버 종료 완료")
 }
-
@@ -5,6 +5,8 @@ go 1.22
 require (
 	github.com/go-redis/redis/v8 v8.11.5
 	github.com/joho/godotenv v1.5.1
+	golang.org/x/net v0.28.0
+	golang.org/x/text v0.17.0
 	golang.org/x/time v0.5.0
 )
 
@@ -0,0 +1,90 @@
+package cache
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Cache는 Gateway 채팅 응답 캐시 저장소
+// Redis(RedisClient)와 로컬 개발/테스트용 인메모리 LRU(MemoryCache) 구현을 CACHE_BACKEND로 선택한다.
+// 모든 구현이 제공해야 하는 최소 기능만 포함하며, 나머지 기능은 아래의 작은 인터페이스로 나누어
+// 사용하는 쪽에서 타입 단언으로 확인한다 (지원하지 않는 구현이면 해당 기능을 건너뜀).
+type Cache interface {
+	Get(query string) (*CachedResponse, error) // 만료된 항목은 미스
+	Set(query, response string, ttl time.Duration) error
+	Delete(query string) error
+	Key(query string) string // 쿼리 정규화 캐시 키
+	IsConnected() bool
+	GetStats() (map[string]any, error)
+	Close() error
+}
+
+// StaleReader는 만료 후 stale 보관 기간 동안 항목을 유지하는 캐시 (Backend 장애 시 fallback)
+type StaleReader interface {
+	GetStale(query string) (*CachedResponse, error) // stale 보관 기간 중인 항목도 반환
+	SetStaleGrace(grace time.Duration)
+}
+
+// ChunkWriter는 SSE 원본 청크 경계를 보존해 저장하는 캐시 (SSE_STORE_CHUNKS)
+type ChunkWriter interface {
+	SetChunks(query string, chunks []string, ttl time.Duration) error
+}
+
+// NegativeWriter는 404/빈 답변을 네거티브 항목으로 저장하는 캐시 (NEGATIVE_CACHE_TTL)
+type NegativeWriter interface {
+	SetNegative(query string, status int, body string, ttl time.Duration) error
+}
+
+// ResponseStore는 일반 엔드포인트 HTTP 응답을 요청 기반 키(RequestKey)로 저장하는 캐시 (CACHEABLE_PATHS)
+type ResponseStore interface {
+	GetResponse(key string) (*CachedHTTPResponse, error)
+	SetResponse(key string, status int, header http.Header, body []byte, ttl time.Duration) error
+}
+
+// Exporter는 채팅 캐시 백업/복원을 지원하는 캐시 (/admin/cache/export, /admin/cache/import)
+type Exporter interface {
+	Export(ctx context.Context, fn func(*ExportEntry) error) error
+	Import(ctx context.Context, entry *ExportEntry, ttl time.Duration) error
+}
+
+// Diagnoser는 진단 리포트용 연결 확인/항목 수/명령 지표를 제공하는 캐시 (/admin/diagnostics)
+type Diagnoser interface {
+	Ping(ctx context.Context) error
+	Count(ctx context.Context) (int, error)
+	Metrics() map[string]OpMetrics
+}
+
+// ConnectionStatter는 연결 상태와 끊김/재연결 횟수를 제공하는 캐시 (/api/cache/stats의 connection)
+type ConnectionStatter interface {
+	ConnectionStats() ConnectionStats
+}
+
+// KeyVersioner는 캐시 키 공간을 바꿀 수 있는 캐시
+type KeyVersioner interface {
+	SetKeyVersion(version string) // CACHE_VERSION: 바꾸면 기존 채팅 캐시 전체가 미스
+	KeyVersion() string
+	SetKeyScope(scope func(query string) string) // 쿼리 분류별 키 공간 (QUERY_ROUTES_FILE)
+}
+
+var (
+	_ Cache = (*RedisClient)(nil)
+	_ Cache = (*MemoryCache)(nil)
+
+	_ StaleReader       = (*RedisClient)(nil)
+	_ ChunkWriter       = (*RedisClient)(nil)
+	_ NegativeWriter    = (*RedisClient)(nil)
+	_ ResponseStore     = (*RedisClient)(nil)
+	_ Exporter          = (*RedisClient)(nil)
+	_ Diagnoser         = (*RedisClient)(nil)
+	_ KeyVersioner      = (*RedisClient)(nil)
+	_ ConnectionStatter = (*RedisClient)(nil) // Redis 전용 (인메모리 캐시는 연결 없음)
+
+	_ StaleReader    = (*MemoryCache)(nil)
+	_ ChunkWriter    = (*MemoryCache)(nil)
+	_ NegativeWriter = (*MemoryCache)(nil)
+	_ ResponseStore  = (*MemoryCache)(nil)
+	_ Exporter       = (*MemoryCache)(nil)
+	_ Diagnoser      = (*MemoryCache)(nil)
+	_ KeyVersioner   = (*MemoryCache)(nil)
+)
@@ -0,0 +1,124 @@
+package cache
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	// accessSetKey는 캐시 키별 마지막 접근 시각을 기록하는 sorted set (score = Unix 초)
+	accessSetKey = "cache:access"
+	// evictionBatch는 한 번에 조회/삭제하는 최대 키 수
+	evictionBatch = 500
+)
+
+// touch는 캐시 키의 마지막 접근 시각 갱신 (LRU 추적용, 실패는 무시)
+// LRU 제거가 꺼져 있으면 정리하는 주체가 없으므로 기록하지 않음
+func (r *RedisClient) touch(key string) {
+	if !r.lru.Load() {
+		return
+	}
+	r.client.ZAdd(r.ctx, accessSetKey, &redis.Z{
+		Score:  float64(time.Now().Unix()),
+		Member: key,
+	})
+}
+
+// EvictLRU는 캐시 항목 수가 maxEntries를 넘으면 가장 오래 접근되지 않은 항목부터 삭제
+// 삭제한 항목 수 반환
+func (r *RedisClient) EvictLRU(ctx context.Context, maxEntries int) (int, error) {
+	count, err := r.Count(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	excess := count - maxEntries
+	evicted := 0
+	for evicted < excess {
+		oldest, err := r.client.ZRange(ctx, accessSetKey, 0, int64(min(excess-evicted, evictionBatch))-1).Result()
+		if err != nil {
+			return evicted, err
+		}
+		if len(oldest) == 0 {
+			break // 접근 기록이 없는 항목은 TTL 만료에 맡김
+		}
+
+		// 응답 키와 함께 같은 해시의 시맨틱 캐시 임베딩도 삭제
+		embeddings := make([]string, 0, len(oldest)*2)
+		for _, key := range oldest {
+			hash := strings.TrimPrefix(key, chatKeyPrefix)
+			embeddings = append(embeddings, semanticKeyPrefix+hash, vectorKeyPrefix+hash)
+		}
+
+		pipe := r.client.TxPipeline()
+		dels := make([]*redis.IntCmd, len(oldest))
+		for i, key := range oldest {
+			dels[i] = pipe.Del(ctx, key)
+		}
+		pipe.Del(ctx, embeddings...)
+		pipe.ZRem(ctx, accessSetKey, toMembers(oldest)...)
+		if _, err := pipe.Exec(ctx); err != nil {
+			return evicted, err
+		}
+
+		// TTL로 이미 만료된 키는 접근 기록만 정리되고 개수에 포함하지 않음
+		for _, del := range dels {
+			evicted += int(del.Val())
+		}
+	}
+
+	return evicted, nil
+}
+
+// PruneAccessLog는 maxAge보다 오래 접근되지 않은 기록 삭제
+// 해당 키는 TTL로 이미 만료되었으므로 sorted set이 무한히 커지지 않도록 정리
+func (r *RedisClient) PruneAccessLog(ctx context.Context, maxAge time.Duration) error {
+	cutoff := strconv.FormatInt(time.Now().Add(-maxAge).Unix(), 10)
+	return r.client.ZRemRangeByScore(ctx, accessSetKey, "-inf", "("+cutoff).Err()
+}
+
+// StartEviction은 interval마다 LRU 제거를 실행하는 백그라운드 루틴 시작
+// maxAge는 항목의 최대 수명(TTL + stale 보관 기간)으로, 지난 접근 기록을 정리하는 데 사용
+// 호출 이후의 캐시 저장/히트부터 접근 시각을 기록
+func (r *RedisClient) StartEviction(ctx context.Context, maxEntries int, interval, maxAge time.Duration) {
+	r.lru.Store(true)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			if err := r.PruneAccessLog(ctx, maxAge); err != nil {
+				log.Printf("⚠️ 캐시 접근 기록 정리 실패: %v", err)
+			}
+
+			evicted, err := r.EvictLRU(ctx, maxEntries)
+			if err != nil {
+				log.Printf("⚠️ LRU 캐시 제거 실패: %v", err)
+				continue
+			}
+			if evicted > 0 {
+				log.Printf("🧹 LRU 캐시 제거: %d개 (최대 %d개)", evicted, maxEntries)
+			}
+		}
+	}()
+}
+
+// toMembers는 ZRem 인자로 쓰기 위해 []string을 []interface{}로 변환
+func toMembers(keys []string) []interface{} {
+	members := make([]interface{}, len(keys))
+	for i, key := range keys {
+		members[i] = key
+	}
+	return members
+}
@@ -0,0 +1,129 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// exportScanCount는 내보내기 SCAN 한 번에 요청하는 키 수 (페이지마다 MGET 한 번)
+const exportScanCount = 500
+
+// ExportEntry는 백업 파일(NDJSON) 한 줄: 채팅 캐시 항목과 저장 위치 키
+// 키를 함께 보관하여 테넌트/클라이언트 지정 키 항목도 같은 키로 복원한다.
+type ExportEntry struct {
+	Key string `json:"key,omitempty"`
+	*CachedResponse
+}
+
+// importKey는 복원할 키 (백업에 채팅 캐시 키가 없으면 현재 버전의 쿼리 키)
+func importKey(keys *keySpace, entry *ExportEntry) string {
+	if strings.HasPrefix(entry.Key, chatKeyPrefix) {
+		return entry.Key
+	}
+	return keys.key(keys.get(), entry.Query)
+}
+
+// importEntry는 복원 시각 기준으로 논리적 만료 시각을 다시 계산한 항목
+func importEntry(entry *ExportEntry, ttl time.Duration) *CachedResponse {
+	cached := *entry.CachedResponse
+	cached.ExpiresAt = time.Now().Add(ttl)
+	return &cached
+}
+
+// Export는 채팅 캐시 항목(chat:*)을 SCAN 페이지 단위로 읽어 fn에 하나씩 전달 (전체를 메모리에 모으지 않음)
+// 읽는 사이 만료된 키와 해석할 수 없는 값은 건너뛰며, fn이 에러를 반환하면 중단
+func (r *RedisClient) Export(ctx context.Context, fn func(*ExportEntry) error) error {
+	var cursor uint64
+	for {
+		keys, next, err := r.client.Scan(ctx, cursor, chatKeyPrefix+"*", exportScanCount).Result()
+		if err != nil {
+			return err
+		}
+
+		if len(keys) > 0 {
+			values, err := r.client.MGet(ctx, keys...).Result()
+			if err != nil {
+				return err
+			}
+			for i, value := range values {
+				data, ok := value.(string)
+				if !ok {
+					continue
+				}
+				cached, err := decodeCachedResponse([]byte(data))
+				if err != nil {
+					continue
+				}
+				if err := fn(&ExportEntry{Key: keys[i], CachedResponse: cached}); err != nil {
+					return err
+				}
+			}
+		}
+
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+// Import는 백업 항목 하나를 ttl로 저장 (논리적 만료는 지금부터 ttl, 네거티브 항목이 아니면 stale 보관 기간 추가)
+func (r *RedisClient) Import(ctx context.Context, entry *ExportEntry, ttl time.Duration) error {
+	key := importKey(&r.keys, entry)
+	cached := importEntry(entry, ttl)
+	if !cached.Negative {
+		ttl += r.staleGrace
+	}
+
+	data, err := r.serializer.Marshal(cached)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	err = r.client.Set(ctx, key, data, ttl).Err()
+	r.metrics.observe(opSet, start, err)
+	if err == nil {
+		r.touch(key)
+	}
+	return err
+}
+
+// Export는 보관 중인 채팅 캐시 항목을 fn에 하나씩 전달 (호출 시점의 스냅샷)
+func (m *MemoryCache) Export(ctx context.Context, fn func(*ExportEntry) error) error {
+	now := time.Now()
+
+	m.mu.Lock()
+	var entries []*ExportEntry
+	for elem := m.order.Front(); elem != nil; elem = elem.Next() {
+		entry := elem.Value.(*memoryEntry)
+		cached, ok := entry.value.(*CachedResponse)
+		if !ok || !strings.HasPrefix(entry.key, chatKeyPrefix) || now.After(entry.deadline) {
+			continue
+		}
+		entries = append(entries, &ExportEntry{Key: entry.key, CachedResponse: cached})
+	}
+	m.mu.Unlock()
+
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Import는 백업 항목 하나를 ttl로 저장 (네거티브 항목이 아니면 stale 보관 기간 추가)
+func (m *MemoryCache) Import(_ context.Context, entry *ExportEntry, ttl time.Duration) error {
+	key := importKey(&m.keys, entry)
+	cached := importEntry(entry, ttl)
+	if !cached.Negative {
+		ttl += m.staleGrace
+	}
+	m.store(key, cached, ttl)
+	return nil
+}
@@ -0,0 +1,50 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// lockKeyPrefix는 캐시 계산 잠금 키 접두사
+const lockKeyPrefix = "lock:"
+
+// Locker는 여러 Gateway 인스턴스 간 캐시 계산 잠금 (cache stampede 방지)
+// Redis 구현만 지원하며, 인메모리 캐시는 프로세스 내 요청 합치기로 충분하다.
+type Locker interface {
+	// TryLock은 key에 대한 잠금을 시도 (이미 잠겨 있으면 acquired=false)
+	// 획득한 경우 release로 해제하며, 해제하지 않아도 ttl 후 자동 만료된다.
+	TryLock(ctx context.Context, key string, ttl time.Duration) (release func(), acquired bool, err error)
+}
+
+var _ Locker = (*RedisClient)(nil)
+
+// unlockScript는 자신이 획득한 잠금만 해제 (만료 후 다른 인스턴스가 얻은 잠금은 유지)
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// TryLock은 SET NX PX로 잠금 시도
+func (r *RedisClient) TryLock(ctx context.Context, key string, ttl time.Duration) (func(), bool, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return nil, false, err
+	}
+	value := hex.EncodeToString(token)
+
+	lockKey := lockKeyPrefix + key
+	acquired, err := r.client.SetNX(ctx, lockKey, value, ttl).Result()
+	if err != nil || !acquired {
+		return nil, false, err
+	}
+
+	release := func() {
+		unlockScript.Run(context.Background(), r.client, []string{lockKey}, value)
+	}
+	return release, true, nil
+}
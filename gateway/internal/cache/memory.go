@@ -0,0 +1,272 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryCache는 프로세스 내 LRU 캐시 (로컬 개발/테스트용, Redis 불필요)
+// 용량을 넘으면 가장 오래 사용되지 않은 항목부터 제거하며, 인스턴스 간 공유되지 않는다.
+type MemoryCache struct {
+	mu         sync.Mutex
+	capacity   int
+	items      map[string]*list.Element
+	order      *list.List // 앞쪽이 최근 사용
+	staleGrace time.Duration
+	metrics    *redisMetrics
+	keys       keySpace // 캐시 키 버전 (CACHE_VERSION)과 쿼리 분류
+}
+
+// memoryEntry는 LRU 항목 (value는 *CachedResponse 또는 *CachedHTTPResponse)
+type memoryEntry struct {
+	key      string
+	value    any
+	deadline time.Time // 이 시각 이후 완전히 제거 (Redis TTL에 해당)
+}
+
+// NewMemoryCache는 새로운 MemoryCache 생성
+func NewMemoryCache(capacity int) *MemoryCache {
+	return &MemoryCache{
+		capacity: capacity,
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+		metrics:  newRedisMetrics(),
+	}
+}
+
+// SetStaleGrace는 만료된 항목을 fallback용으로 추가 보관할 기간 설정
+func (m *MemoryCache) SetStaleGrace(grace time.Duration) {
+	m.staleGrace = grace
+}
+
+// load는 키의 값을 조회하고 최근 사용으로 표시 (없거나 보관 기간이 지나면 nil)
+func (m *MemoryCache) load(key string) any {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	elem, ok := m.items[key]
+	if !ok {
+		return nil
+	}
+	entry := elem.Value.(*memoryEntry)
+	if time.Now().After(entry.deadline) {
+		m.order.Remove(elem)
+		delete(m.items, key)
+		return nil
+	}
+	m.order.MoveToFront(elem)
+	return entry.value
+}
+
+// store는 키에 값을 저장하고 용량을 넘으면 LRU 항목 제거
+func (m *MemoryCache) store(key string, value any, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	deadline := time.Now().Add(ttl)
+	if elem, ok := m.items[key]; ok {
+		entry := elem.Value.(*memoryEntry)
+		entry.value, entry.deadline = value, deadline
+		m.order.MoveToFront(elem)
+		return
+	}
+
+	m.items[key] = m.order.PushFront(&memoryEntry{key: key, value: value, deadline: deadline})
+	for m.capacity > 0 && m.order.Len() > m.capacity {
+		oldest := m.order.Back()
+		m.order.Remove(oldest)
+		delete(m.items, oldest.Value.(*memoryEntry).key)
+	}
+}
+
+// Key는 쿼리에 대응하는 캐시 키 반환 (Redis와 같은 형식)
+func (m *MemoryCache) Key(query string) string {
+	return m.keys.key(m.keys.get(), query)
+}
+
+// SetKeyVersion은 캐시 키에 섞을 버전 설정
+func (m *MemoryCache) SetKeyVersion(version string) {
+	m.keys.set(version)
+}
+
+// KeyVersion은 현재 캐시 키 버전 반환
+func (m *MemoryCache) KeyVersion() string {
+	return m.keys.get()
+}
+
+// SetKeyScope는 쿼리 분류 함수를 캐시 키에 포함 (트래픽 처리 시작 전에 호출)
+func (m *MemoryCache) SetKeyScope(scope func(query string) string) {
+	m.keys.scope = scope
+}
+
+// Get는 캐시에서 응답 조회 (만료된 stale 항목은 미스로 처리)
+func (m *MemoryCache) Get(query string) (*CachedResponse, error) {
+	cached, err := m.GetStale(query)
+	if err != nil || cached == nil || cached.IsExpired() {
+		return nil, err
+	}
+	return cached, nil
+}
+
+// GetStale는 만료 여부와 관계없이 보관 중인 응답 조회
+func (m *MemoryCache) GetStale(query string) (*CachedResponse, error) {
+	start := time.Now()
+	cached, ok := m.load(m.Key(query)).(*CachedResponse)
+	if !ok {
+		m.metrics.observe(opGet, start, errCacheMiss)
+		return nil, nil
+	}
+	m.metrics.observe(opGet, start, nil)
+	return cached, nil
+}
+
+// Set는 응답을 캐시에 저장 (NormalizeResponse로 정규화)
+func (m *MemoryCache) Set(query, response string, ttl time.Duration) error {
+	start := time.Now()
+	now := time.Now()
+	version := m.keys.get()
+	m.store(m.keys.key(version, query), &CachedResponse{
+		Query:     query,
+		Response:  NormalizeResponse(response),
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+		Version:   version,
+	}, ttl+m.staleGrace)
+	m.metrics.observe(opSet, start, nil)
+	return nil
+}
+
+// SetChunks는 SSE 스트림 응답을 원본 청크 목록과 함께 저장
+func (m *MemoryCache) SetChunks(query string, chunks []string, ttl time.Duration) error {
+	start := time.Now()
+	now := time.Now()
+	version := m.keys.get()
+	m.store(m.keys.key(version, query), &CachedResponse{
+		Query:     query,
+		Response:  NormalizeResponse(strings.Join(chunks, "")),
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+		Version:   version,
+		Chunks:    append([]string(nil), chunks...),
+	}, ttl+m.staleGrace)
+	m.metrics.observe(opSet, start, nil)
+	return nil
+}
+
+// SetNegative는 답이 없는 질문(404 또는 빈 답변)을 네거티브 항목으로 저장
+func (m *MemoryCache) SetNegative(query string, status int, body string, ttl time.Duration) error {
+	start := time.Now()
+	now := time.Now()
+	version := m.keys.get()
+	m.store(m.keys.key(version, query), &CachedResponse{
+		Query:     query,
+		Response:  body,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+		Negative:  true,
+		Status:    status,
+		Version:   version,
+	}, ttl)
+	m.metrics.observe(opSet, start, nil)
+	return nil
+}
+
+// Delete는 캐시에서 항목 삭제
+func (m *MemoryCache) Delete(query string) error {
+	start := time.Now()
+	key := m.Key(query)
+
+	m.mu.Lock()
+	if elem, ok := m.items[key]; ok {
+		m.order.Remove(elem)
+		delete(m.items, key)
+	}
+	m.mu.Unlock()
+
+	m.metrics.observe(opDelete, start, nil)
+	return nil
+}
+
+// GetResponse는 요청 키로 캐시된 HTTP 응답 조회
+func (m *MemoryCache) GetResponse(key string) (*CachedHTTPResponse, error) {
+	start := time.Now()
+	cached, ok := m.load(key).(*CachedHTTPResponse)
+	if !ok {
+		m.metrics.observe(opGet, start, errCacheMiss)
+		return nil, nil
+	}
+	m.metrics.observe(opGet, start, nil)
+	return cached, nil
+}
+
+// SetResponse는 HTTP 응답을 요청 키로 캐시에 저장
+func (m *MemoryCache) SetResponse(key string, status int, header http.Header, body []byte, ttl time.Duration) error {
+	start := time.Now()
+	m.store(key, &CachedHTTPResponse{
+		Status:    status,
+		Header:    header.Clone(),
+		Body:      append([]byte(nil), body...),
+		CreatedAt: time.Now(),
+	}, ttl)
+	m.metrics.observe(opSet, start, nil)
+	return nil
+}
+
+// IsConnected는 항상 true (프로세스 내 캐시)
+func (m *MemoryCache) IsConnected() bool {
+	return true
+}
+
+// Ping은 항상 성공
+func (m *MemoryCache) Ping(context.Context) error {
+	return nil
+}
+
+// Count는 채팅 캐시 항목(chat:*) 개수 조회
+func (m *MemoryCache) Count(context.Context) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	count := 0
+	for key := range m.items {
+		if strings.HasPrefix(key, chatKeyPrefix) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// GetStats는 캐시 통계 조회
+func (m *MemoryCache) GetStats() (map[string]any, error) {
+	count, _ := m.Count(context.Background())
+
+	m.mu.Lock()
+	total := m.order.Len()
+	m.mu.Unlock()
+
+	return map[string]any{
+		"backend":        "memory",
+		"cached_queries": count,
+		"entries":        total,
+		"capacity":       m.capacity,
+		"operations":     m.Metrics(),
+	}, nil
+}
+
+// Metrics는 명령별 지연 시간 지표 반환
+func (m *MemoryCache) Metrics() map[string]OpMetrics {
+	return m.metrics.snapshot()
+}
+
+// Close는 모든 항목 삭제
+func (m *MemoryCache) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.items = make(map[string]*list.Element)
+	m.order.Init()
+	return nil
+}
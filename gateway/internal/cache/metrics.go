@@ -0,0 +1,98 @@
+package cache
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Redis 명령 종류 (메트릭 라벨)
+const (
+	opGet    = "get"
+	opSet    = "set"
+	opDelete = "delete"
+)
+
+// errCacheMiss는 Redis 외 구현에서 미스를 기록할 때 사용 (redis.Nil과 같은 취급)
+var errCacheMiss = errors.New("cache miss")
+
+// opStats는 Redis 명령 하나의 누적 지표
+type opStats struct {
+	count        atomic.Int64
+	errors       atomic.Int64
+	misses       atomic.Int64 // redis.Nil (캐시 미스, 에러 아님)
+	totalLatency atomic.Int64 // 나노초
+	maxLatency   atomic.Int64 // 나노초
+}
+
+// OpMetrics는 Redis 명령별 지표 스냅샷
+type OpMetrics struct {
+	Count        int64   `json:"count"`
+	Errors       int64   `json:"errors"`
+	Misses       int64   `json:"misses"`
+	AvgLatencyMs float64 `json:"avg_latency_ms"`
+	MaxLatencyMs float64 `json:"max_latency_ms"`
+}
+
+// redisMetrics는 Redis 명령 지연 시간/에러 카운터
+type redisMetrics struct {
+	ops map[string]*opStats
+}
+
+func newRedisMetrics() *redisMetrics {
+	return &redisMetrics{
+		ops: map[string]*opStats{
+			opGet:    {},
+			opSet:    {},
+			opDelete: {},
+		},
+	}
+}
+
+// observe는 명령 실행 결과를 기록 (redis.Nil은 미스로 분류)
+func (m *redisMetrics) observe(op string, start time.Time, err error) {
+	stats := m.ops[op]
+	latency := time.Since(start).Nanoseconds()
+
+	stats.count.Add(1)
+	stats.totalLatency.Add(latency)
+	for {
+		max := stats.maxLatency.Load()
+		if latency <= max || stats.maxLatency.CompareAndSwap(max, latency) {
+			break
+		}
+	}
+
+	switch {
+	case err == redis.Nil || err == errCacheMiss:
+		stats.misses.Add(1)
+	case err != nil:
+		stats.errors.Add(1)
+	}
+}
+
+// snapshot은 현재 지표의 복사본 반환
+func (m *redisMetrics) snapshot() map[string]OpMetrics {
+	result := make(map[string]OpMetrics, len(m.ops))
+	for op, stats := range m.ops {
+		count := stats.count.Load()
+		metrics := OpMetrics{
+			Count:        count,
+			Errors:       stats.errors.Load(),
+			Misses:       stats.misses.Load(),
+			MaxLatencyMs: float64(stats.maxLatency.Load()) / float64(time.Millisecond),
+		}
+		if count > 0 {
+			metrics.AvgLatencyMs = float64(stats.totalLatency.Load()) / float64(count) / float64(time.Millisecond)
+		}
+		result[op] = metrics
+	}
+	return result
+}
+
+// Metrics는 Redis 명령별 지연 시간/에러 지표 반환
+func (r *RedisClient) Metrics() map[string]OpMetrics {
+	return r.metrics.snapshot()
+}
@@ -0,0 +1,125 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// connState는 Redis 연결 상태와 끊김/재연결 이벤트 (명령 결과와 백그라운드 헬스체크로 갱신)
+// Redis 재시작 중에도 IsConnected가 매 요청 PING 결과에 따라 흔들리지 않도록 마지막 상태를 보관한다.
+type connState struct {
+	connected   atomic.Bool
+	checking    atomic.Bool  // 백그라운드 헬스체크 실행 중 (StartHealthCheck)
+	changedAt   atomic.Int64 // 마지막 상태 변경 시각 (UnixNano)
+	disconnects atomic.Int64
+	reconnects  atomic.Int64
+}
+
+// ConnectionStats는 /api/cache/stats용 연결 상태 스냅샷
+type ConnectionStats struct {
+	Connected   bool      `json:"connected"`
+	ChangedAt   time.Time `json:"changed_at,omitempty"`
+	Disconnects int64     `json:"disconnects"`
+	Reconnects  int64     `json:"reconnects"`
+}
+
+// observe는 Redis 명령 결과로 연결 상태 갱신 (상태가 바뀌면 로그와 카운터 기록)
+// 서버가 응답한 에러(redis.Nil, WRONGTYPE 등)와 호출자 컨텍스트 취소는 연결 상태와 무관하므로 무시
+func (cs *connState) observe(err error) {
+	if err != nil && (errors.Is(err, redis.Nil) || errors.Is(err, context.Canceled) || isServerError(err)) {
+		return
+	}
+
+	connected := err == nil
+	if cs.connected.Swap(connected) == connected {
+		return
+	}
+	cs.changedAt.Store(time.Now().UnixNano())
+	if connected {
+		cs.reconnects.Add(1)
+		log.Println("✅ Redis 재연결")
+		return
+	}
+	cs.disconnects.Add(1)
+	log.Printf("🔌 Redis 연결 끊김: %v", err)
+}
+
+// stats는 현재 연결 상태 스냅샷
+func (cs *connState) stats() ConnectionStats {
+	stats := ConnectionStats{
+		Connected:   cs.connected.Load(),
+		Disconnects: cs.disconnects.Load(),
+		Reconnects:  cs.reconnects.Load(),
+	}
+	if changed := cs.changedAt.Load(); changed > 0 {
+		stats.ChangedAt = time.Unix(0, changed)
+	}
+	return stats
+}
+
+// isServerError는 Redis 서버가 보낸 에러 응답인지 확인 (연결은 정상)
+func isServerError(err error) bool {
+	var redisErr redis.Error
+	return errors.As(err, &redisErr)
+}
+
+// connHook은 모든 Redis 명령 결과를 연결 상태에 반영하는 go-redis 훅
+type connHook struct {
+	state *connState
+}
+
+func (h connHook) BeforeProcess(ctx context.Context, _ redis.Cmder) (context.Context, error) {
+	return ctx, nil
+}
+
+func (h connHook) AfterProcess(_ context.Context, cmd redis.Cmder) error {
+	h.state.observe(cmd.Err())
+	return nil
+}
+
+func (h connHook) BeforeProcessPipeline(ctx context.Context, _ []redis.Cmder) (context.Context, error) {
+	return ctx, nil
+}
+
+func (h connHook) AfterProcessPipeline(_ context.Context, cmds []redis.Cmder) error {
+	for _, cmd := range cmds {
+		h.state.observe(cmd.Err())
+	}
+	return nil
+}
+
+// StartHealthCheck는 interval마다 PING으로 연결 상태를 갱신하는 백그라운드 루틴 시작 (REDIS_HEALTH_INTERVAL)
+// 실행 중에는 IsConnected가 PING 없이 마지막 상태를 반환하므로, 끊긴 동안 요청마다 PING하지 않고
+// Redis가 돌아오면 다음 확인(또는 명령 성공) 즉시 캐시 사용을 재개한다.
+func (r *RedisClient) StartHealthCheck(ctx context.Context, interval time.Duration) {
+	r.conn.checking.Store(true)
+	go func() {
+		defer r.conn.checking.Store(false)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			// 결과는 connHook이 연결 상태에 반영
+			pingCtx, cancel := context.WithTimeout(ctx, interval)
+			r.client.Ping(pingCtx)
+			cancel()
+		}
+	}()
+}
+
+// ConnectionStats는 현재 Redis 연결 상태와 끊김/재연결 횟수
+func (r *RedisClient) ConnectionStats() ConnectionStats {
+	return r.conn.stats()
+}
@@ -4,18 +4,28 @@ import (
 	"context"
 	"crypto/md5"
 	"encoding/hex"
-	"encoding/json"
 	"log"
+	"net/http"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-redis/redis/v8"
+	"golang.org/x/text/unicode/norm"
 )
 
 // RedisClient는 Redis 연결을 관리하는 클라이언트
 type RedisClient struct {
-	client *redis.Client
-	ctx    context.Context
+	client     *redis.Client
+	ctx        context.Context
+	staleGrace time.Duration // 만료 후에도 장애 대비용으로 보관하는 기간
+	metrics    *redisMetrics
+	keys       keySpace        // 캐시 키 버전 (CACHE_VERSION)과 쿼리 분류
+	sizeStats  sizeStatsConfig // GetStats 항목 크기 집계 (CACHE_SIZE_STATS)
+	writer     *writeBehind    // write-behind 저장 (CACHE_WRITE_BEHIND 설정 시, 없으면 nil = 즉시 SET)
+	conn       *connState      // 연결 상태와 끊김/재연결 이벤트
+	serializer Serializer      // 채팅 캐시 항목 저장 형식 (CACHE_SERIALIZER, 조회는 저장된 형식을 자동 판별)
+	lru        atomic.Bool     // 접근 시각 기록 여부 (StartEviction 호출 시, CACHE_MAX_ENTRIES > 0)
 }
 
 // CachedResponse는 캐시된 응답 구조체
@@ -23,58 +33,179 @@ type CachedResponse struct {
 	Query     string    `json:"query"`
 	Response  string    `json:"response"`
 	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+
+	// 네거티브 캐시 항목 (404 또는 빈 답변, NEGATIVE_CACHE_TTL)
+	// Status가 200이 아니면 Response는 Backend 응답 바디 원문
+	Negative bool `json:"negative,omitempty"`
+	Status   int  `json:"status,omitempty"`
+
+	// 저장 시점의 캐시 키 버전 (디버깅용)
+	Version string `json:"version,omitempty"`
+
+	// SSE 스트림의 원본 토큰 청크 (SSE_STORE_CHUNKS, 재생 시 같은 경계로 전송)
+	// 이 필드가 없는 항목은 Response를 임의 크기로 나눠 재생
+	Chunks []string `json:"chunks,omitempty"`
+}
+
+// StatusCode는 캐시된 응답의 HTTP 상태 코드 (기록되지 않았으면 200)
+func (c *CachedResponse) StatusCode() int {
+	if c.Status == 0 {
+		return http.StatusOK
+	}
+	return c.Status
+}
+
+// IsExpired는 논리적 TTL이 지났는지 확인 (stale 보관 기간 중인 항목)
+func (c *CachedResponse) IsExpired() bool {
+	return !c.ExpiresAt.IsZero() && time.Now().After(c.ExpiresAt)
 }
 
+// defaultMaxRetryBackoff는 재시도 대기 시간 상한 (go-redis 기본값)
+const defaultMaxRetryBackoff = 512 * time.Millisecond
+
 // NewRedisClient는 새로운 Redis 클라이언트 생성
-func NewRedisClient(addr, password string) *RedisClient {
+// 연결 끊김(EOF, 연결 거부 등)으로 실패한 명령은 retryBackoff부터 늘어나는 간격으로 최대 maxRetries번 재시도 (0이면 재시도 안 함)
+// Redis 재시작 중 풀의 끊긴 연결로 실패한 Get/Set도 새 연결로 다시 실행된다.
+func NewRedisClient(addr, password string, maxRetries int, retryBackoff time.Duration) *RedisClient {
+	if maxRetries <= 0 {
+		maxRetries = -1 // go-redis: -1이면 재시도 안 함 (0은 기본값 3)
+	}
 	client := redis.NewClient(&redis.Options{
-		Addr:     addr,
-		Password: password,
-		DB:       0,
+		Addr:            addr,
+		Password:        password,
+		DB:              0,
+		MaxRetries:      maxRetries,
+		MinRetryBackoff: retryBackoff,
+		MaxRetryBackoff: max(retryBackoff, defaultMaxRetryBackoff),
 	})
 
 	ctx := context.Background()
+	conn := &connState{}
 
 	// 연결 테스트
 	if _, err := client.Ping(ctx).Result(); err != nil {
 		log.Printf("⚠️ Redis 연결 실패: %v (캐시 비활성화)", err)
 	} else {
+		conn.connected.Store(true)
 		log.Println("✅ Redis 연결 성공")
 	}
+	client.AddHook(connHook{state: conn})
 
 	return &RedisClient{
-		client: client,
-		ctx:    ctx,
+		client:     client,
+		ctx:        ctx,
+		metrics:    newRedisMetrics(),
+		conn:       conn,
+		serializer: jsonSerializer{},
 	}
 }
 
-// Close는 Redis 연결 종료
+// SetSerializer는 채팅 캐시 항목을 저장할 형식 설정 (트래픽 처리 시작 전에 호출)
+// 기존 형식으로 저장된 항목도 계속 읽을 수 있으므로 형식을 바꿔도 캐시를 비울 필요가 없다.
+func (r *RedisClient) SetSerializer(serializer Serializer) {
+	r.serializer = serializer
+}
+
+// SetStaleGrace는 만료된 항목을 Backend 장애 시 fallback으로 쓰기 위해 추가 보관할 기간 설정
+func (r *RedisClient) SetStaleGrace(grace time.Duration) {
+	r.staleGrace = grace
+}
+
+// Close는 Redis 연결 종료 (write-behind 모드면 큐에 남은 저장을 먼저 기록)
 func (r *RedisClient) Close() error {
+	if r.writer != nil {
+		r.writer.close()
+	}
 	return r.client.Close()
 }
 
 // IsConnected는 Redis 연결 상태 확인
+// 백그라운드 헬스체크(StartHealthCheck) 실행 중이면 PING 없이 마지막 상태 반환
 func (r *RedisClient) IsConnected() bool {
+	if r.conn.checking.Load() {
+		return r.conn.connected.Load()
+	}
 	_, err := r.client.Ping(r.ctx).Result()
 	return err == nil
 }
 
+// Ping은 주어진 컨텍스트(타임아웃)로 Redis 연결 확인
+func (r *RedisClient) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
+
+// Count는 캐시 항목(chat:*) 개수 조회 (SCAN 사용, CACHE_SHARDS 설정 시 버킷별 동시 조회)
+func (r *RedisClient) Count(ctx context.Context) (int, error) {
+	_, count, err := r.scanKeys(ctx, false)
+	return count, err
+}
+
 // generateCacheKey는 쿼리에서 캐시 키 생성
-func generateCacheKey(query string) string {
-	// 쿼리 정규화: 소문자 변환, 공백 정리
-	normalized := strings.ToLower(strings.TrimSpace(query))
-	normalized = strings.Join(strings.Fields(normalized), " ")
+// namespace(버전, 쿼리 분류, 테넌트)가 있으면 해시에 포함하여 다른 키 공간을 사용 (비어 있으면 기존 키와 동일)
+func generateCacheKey(namespace, query string) string {
+	normalized := normalizeQuery(query)
+	if namespace != "" {
+		normalized = namespace + "\n" + normalized
+	}
 
 	// MD5 해시 생성
 	hash := md5.Sum([]byte(normalized))
-	return "chat:" + hex.EncodeToString(hash[:])
+	return chatKeyPrefix + hex.EncodeToString(hash[:])
+}
+
+// zeroWidthRunes는 정규화 시 제거하는 폭 없는 문자 (NFKC로 사라지지 않고 복사/붙여넣기로 섞여 들어옴)
+var zeroWidthRunes = strings.NewReplacer("\u200b", "", "\u2060", "", "\ufeff", "")
+
+// normalizeQuery는 캐시 키용 쿼리 정규화
+// NFKC로 전각 문자/호환 문자(전각 숫자·영문, 합성/분해 한글 등)를 통일하고, 폭 없는 문자를 제거한 뒤
+// 소문자로 바꾸고 모든 유니코드 공백(NBSP, 전각 공백 포함)을 ASCII 공백 하나로 합친다.
+func normalizeQuery(query string) string {
+	normalized := zeroWidthRunes.Replace(norm.NFKC.String(query))
+	normalized = strings.ToLower(normalized)
+	return strings.Join(strings.Fields(normalized), " ")
+}
+
+// Key는 쿼리에 대응하는 Redis 키 반환 (현재 CACHE_VERSION 적용)
+func (r *RedisClient) Key(query string) string {
+	return r.keys.key(r.keys.get(), query)
+}
+
+// SetKeyVersion은 캐시 키에 섞을 버전 설정
+func (r *RedisClient) SetKeyVersion(version string) {
+	r.keys.set(version)
+}
+
+// KeyVersion은 현재 캐시 키 버전 반환
+func (r *RedisClient) KeyVersion() string {
+	return r.keys.get()
 }
 
-// Get는 캐시에서 응답 조회
+// SetKeyScope는 쿼리 분류 함수를 캐시 키에 포함 (트래픽 처리 시작 전에 호출)
+func (r *RedisClient) SetKeyScope(scope func(query string) string) {
+	r.keys.scope = scope
+}
+
+// Get는 캐시에서 응답 조회 (만료된 stale 항목은 미스로 처리)
 func (r *RedisClient) Get(query string) (*CachedResponse, error) {
-	key := generateCacheKey(query)
+	cached, err := r.GetStale(query)
+	if err != nil || cached == nil {
+		return nil, err
+	}
+	if cached.IsExpired() {
+		return nil, nil
+	}
+	r.touch(r.Key(query))
+	return cached, nil
+}
 
+// GetStale는 만료 여부와 관계없이 보관 중인 응답 조회 (Backend 장애 fallback용)
+func (r *RedisClient) GetStale(query string) (*CachedResponse, error) {
+	key := r.Key(query)
+
+	start := time.Now()
 	data, err := r.client.Get(r.ctx, key).Bytes()
+	r.metrics.observe(opGet, start, err)
 	if err == redis.Nil {
 		return nil, nil // 캐시 미스
 	}
@@ -82,36 +213,90 @@ func (r *RedisClient) Get(query string) (*CachedResponse, error) {
 		return nil, err
 	}
 
-	var cached CachedResponse
-	if err := json.Unmarshal(data, &cached); err != nil {
-		return nil, err
-	}
+	return decodeCachedResponse(data)
+}
 
-	return &cached, nil
+// NormalizeResponse는 캐시에 저장할 답변 텍스트 정규화
+// 동기(/api/chat) 응답과 스트리밍(/api/chat/stream) 누적 응답이 같은 형태로 저장되어
+// 어느 경로로 캐시되었든 다른 경로에서 그대로 재생할 수 있도록 함
+func NormalizeResponse(response string) string {
+	response = strings.ReplaceAll(response, "\r\n", "\n")
+	return strings.TrimSpace(response)
 }
 
-// Set는 응답을 캐시에 저장
+// Set는 응답을 캐시에 저장 (NormalizeResponse로 정규화)
 func (r *RedisClient) Set(query, response string, ttl time.Duration) error {
-	key := generateCacheKey(query)
+	now := time.Now()
+	// stale 보관 기간만큼 Redis TTL을 늘려 장애 시 fallback으로 사용
+	return r.store(&CachedResponse{
+		Query:     query,
+		Response:  NormalizeResponse(response),
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}, ttl+r.staleGrace)
+}
 
-	cached := CachedResponse{
+// SetChunks는 SSE 스트림 응답을 원본 청크 목록과 함께 저장
+// Response에는 청크를 이어 붙여 정규화한 텍스트를 저장하므로 동기 채팅 히트에도 그대로 사용된다.
+func (r *RedisClient) SetChunks(query string, chunks []string, ttl time.Duration) error {
+	now := time.Now()
+	return r.store(&CachedResponse{
 		Query:     query,
-		Response:  response,
-		CreatedAt: time.Now(),
-	}
+		Response:  NormalizeResponse(strings.Join(chunks, "")),
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+		Chunks:    chunks,
+	}, ttl+r.staleGrace)
+}
+
+// SetNegative는 답이 없는 질문(404 또는 빈 답변)을 네거티브 항목으로 저장
+// 상태 코드와 바디를 그대로 보관하며 stale 보관 기간은 적용하지 않음
+func (r *RedisClient) SetNegative(query string, status int, body string, ttl time.Duration) error {
+	now := time.Now()
+	return r.store(&CachedResponse{
+		Query:     query,
+		Response:  body,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+		Negative:  true,
+		Status:    status,
+	}, ttl)
+}
 
-	data, err := json.Marshal(cached)
+// store는 항목을 현재 버전의 쿼리 키로 저장
+func (r *RedisClient) store(cached *CachedResponse, ttl time.Duration) error {
+	cached.Version = r.keys.get()
+	key := r.keys.key(cached.Version, cached.Query)
+
+	data, err := r.serializer.Marshal(cached)
 	if err != nil {
 		return err
 	}
 
-	return r.client.Set(r.ctx, key, data, ttl).Err()
+	if r.writer != nil {
+		return r.writer.enqueue(pendingWrite{key: key, data: data, ttl: ttl, touch: true})
+	}
+
+	start := time.Now()
+	err = r.client.Set(r.ctx, key, data, ttl).Err()
+	r.metrics.observe(opSet, start, err)
+	if err == nil {
+		r.touch(key)
+	}
+	return err
 }
 
 // Delete는 캐시에서 항목 삭제
 func (r *RedisClient) Delete(query string) error {
-	key := generateCacheKey(query)
-	return r.client.Del(r.ctx, key).Err()
+	key := r.Key(query)
+
+	start := time.Now()
+	err := r.client.Del(r.ctx, key).Err()
+	r.metrics.observe(opDelete, start, err)
+	if err == nil && r.lru.Load() {
+		r.client.ZRem(r.ctx, accessSetKey, key)
+	}
+	return err
 }
 
 // GetStats는 캐시 통계 조회
@@ -121,17 +306,31 @@ func (r *RedisClient) GetStats() (map[string]any, error) {
 		return nil, err
 	}
 
-	// 키 개수 조회
-	keys, err := r.client.Keys(r.ctx, "chat:*").Result()
+	// 키 목록 조회 (CACHE_SHARDS 설정 시 버킷별 동시 SCAN)
+	keys, _, err := r.scanKeys(r.ctx, true)
 	if err != nil {
 		return nil, err
 	}
 
-	return map[string]any{
+	stats := map[string]any{
+		"backend":        "redis",
 		"cached_queries": len(keys),
 		"info":           info,
-	}, nil
-}
-
-
+		"operations":     r.Metrics(),
+		"connection":     r.ConnectionStats(),
+		"serializer":     r.serializer.Name(),
+	}
+	if r.writer != nil {
+		stats["write_queue"] = r.writer.stats()
+	}
 
+	// 항목 크기 (CACHE_SIZE_STATS, MEMORY USAGE 호출 비용이 있어 opt-in)
+	if r.sizeStats.method == SizeStatsSample || r.sizeStats.method == SizeStatsFull {
+		sizes, err := r.entrySizes(r.ctx, keys)
+		if err != nil {
+			sizes = map[string]any{"method": r.sizeStats.method, "error": err.Error()}
+		}
+		stats["size"] = sizes
+	}
+	return stats, nil
+}
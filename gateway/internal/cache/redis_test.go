@@ -0,0 +1,50 @@
+package cache
+
+import "testing"
+
+func TestNormalizeQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"ascii", "What is RAG", "what is rag"},
+		{"surrounding and repeated spaces", "  what   is\trag\n", "what is rag"},
+		{"fullwidth latin", "ＲＡＧ란", "rag란"},
+		{"fullwidth digits", "ＧＰＴ－４", "gpt-4"},
+		{"decomposed hangul", "\u1100\u1161\u11a8", "\uac01"},
+		{"compatibility jamo", "\u3131", "\u1100"},
+		{"nbsp", "what\u00a0is", "what is"},
+		{"ideographic space", "검색\u3000증강", "검색 증강"},
+		{"zero width space", "r\u200bag", "rag"},
+		{"word joiner", "r\u2060ag", "rag"},
+		{"byte order mark", "\ufeffrag", "rag"},
+		{"zero width between words", "what \u200bis", "what is"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeQuery(tt.query); got != tt.want {
+				t.Errorf("normalizeQuery(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeQueryEquivalentKeys(t *testing.T) {
+	c := NewMemoryCache(10)
+	equivalent := [][]string{
+		{"RAG란 무엇인가", "ＲＡＧ란 무엇인가", "rag란\u3000무엇인가", "R\u200bAG란  무엇인가"},
+		{"\uac01", "\u1100\u1161\u11a8"},
+	}
+	for _, group := range equivalent {
+		want := c.Key(group[0])
+		for _, query := range group[1:] {
+			if got := c.Key(query); got != want {
+				t.Errorf("Key(%q) = %q, want %q (same as %q)", query, got, want, group[0])
+			}
+		}
+	}
+	if c.Key("rag") == c.Key("rag2") {
+		t.Error("different queries share a key")
+	}
+}
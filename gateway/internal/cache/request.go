@@ -0,0 +1,107 @@
+package cache
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// CachedHTTPResponse는 일반 엔드포인트 응답 캐시 구조체
+type CachedHTTPResponse struct {
+	Status    int         `json:"status"`
+	Header    http.Header `json:"header"`
+	Body      []byte      `json:"body"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// RequestKey는 전체 요청(메서드, 경로, 정렬된 쿼리, 정규화된 바디)에서 캐시 키 생성
+// 채팅 전용 쿼리 키가 적용되지 않는 일반 GET/POST 응답 캐싱에 사용
+// namespace(테넌트)가 있으면 해시에 포함하여 다른 키 공간을 사용 (비어 있으면 기존 키와 동일)
+func RequestKey(namespace, method, path string, query url.Values, body []byte) string {
+	var b strings.Builder
+	if namespace != "" {
+		b.WriteString(namespace)
+		b.WriteString("\n")
+	}
+	b.WriteString(method)
+	b.WriteString(" ")
+	b.WriteString(path)
+	b.WriteString("?")
+	b.WriteString(query.Encode()) // Encode는 키 기준 정렬
+	b.WriteString("\n")
+	b.Write(canonicalBody(body))
+
+	hash := md5.Sum([]byte(b.String()))
+	return "http:" + hex.EncodeToString(hash[:])
+}
+
+// canonicalBody는 JSON 바디를 필드 순서/공백과 무관한 형태로 정규화
+// JSON이 아니면 원본 그대로 사용
+func canonicalBody(body []byte) []byte {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return body
+	}
+
+	// encoding/json은 map 키를 정렬하여 직렬화
+	canonical, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return canonical
+}
+
+// GetResponse는 요청 키로 캐시된 HTTP 응답 조회
+func (r *RedisClient) GetResponse(key string) (*CachedHTTPResponse, error) {
+	start := time.Now()
+	data, err := r.client.Get(r.ctx, key).Bytes()
+	r.metrics.observe(opGet, start, err)
+	if err == redis.Nil {
+		return nil, nil // 캐시 미스
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var cached CachedHTTPResponse
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return nil, err
+	}
+
+	return &cached, nil
+}
+
+// SetResponse는 HTTP 응답을 요청 키로 캐시에 저장
+func (r *RedisClient) SetResponse(key string, status int, header http.Header, body []byte, ttl time.Duration) error {
+	cached := CachedHTTPResponse{
+		Status:    status,
+		Header:    header,
+		Body:      body,
+		CreatedAt: time.Now(),
+	}
+
+	data, err := json.Marshal(cached)
+	if err != nil {
+		return err
+	}
+
+	if r.writer != nil {
+		return r.writer.enqueue(pendingWrite{key: key, data: data, ttl: ttl})
+	}
+
+	start := time.Now()
+	err = r.client.Set(r.ctx, key, data, ttl).Err()
+	r.metrics.observe(opSet, start, err)
+	return err
+}
@@ -0,0 +1,294 @@
+package cache
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devbrain/gateway/internal/embedding"
+)
+
+const (
+	// semanticKeyPrefix는 쿼리 임베딩 저장 키 접두사 (Go 내부 전수 비교용 JSON)
+	semanticKeyPrefix = "emb:"
+	// vectorKeyPrefix는 RediSearch 벡터 인덱스에 포함되는 HASH 키 접두사
+	vectorKeyPrefix = "embv:"
+	// vectorIndexName은 RediSearch 벡터 인덱스 이름
+	vectorIndexName = "idx:emb"
+	// semanticTopK는 유사 질문 검색 시 가져오는 후보 수
+	semanticTopK = 5
+)
+
+// SemanticCache는 임베딩 유사도로 의미가 같은 질문의 캐시를 찾는 시맨틱 캐시
+// 구체적인 임베딩 모델이 아닌 Embedder 인터페이스에 의존한다.
+type SemanticCache struct {
+	redis     *RedisClient
+	embedder  embedding.Embedder
+	threshold float64 // 유사도 임계값 (0.0 ~ 1.0)
+
+	// RediSearch 벡터 인덱스 사용 여부 (EnableVectorIndex 성공 시)
+	useIndex   bool
+	indexMu    sync.Mutex
+	indexReady bool
+}
+
+// semanticEntry는 Redis에 저장되는 쿼리 임베딩
+type semanticEntry struct {
+	Query  string    `json:"query"`
+	Vector []float32 `json:"vector"`
+}
+
+// SemanticMatch는 시맨틱 캐시 조회 결과
+type SemanticMatch struct {
+	Key      string          `json:"key"`
+	Score    float64         `json:"score"` // 코사인 유사도
+	Response *CachedResponse `json:"response"`
+}
+
+// NewSemanticCache는 새로운 SemanticCache 생성
+func NewSemanticCache(redis *RedisClient, embedder embedding.Embedder, threshold float64) *SemanticCache {
+	return &SemanticCache{
+		redis:     redis,
+		embedder:  embedder,
+		threshold: threshold,
+	}
+}
+
+// EnableVectorIndex는 RediSearch가 있으면 벡터 인덱스(FT.SEARCH KNN)를 사용하도록 설정
+// RediSearch가 없으면 에러를 반환하고 Go 내부 전수 비교 방식을 유지
+// 재시작 전에 만든 인덱스가 있으면(FT.INFO) 바로 사용하고, 없으면 임베딩 차원을 알 수 있는 첫 저장 시점에 생성
+func (s *SemanticCache) EnableVectorIndex(ctx context.Context) error {
+	if err := s.redis.client.Do(ctx, "FT._LIST").Err(); err != nil {
+		return fmt.Errorf("RediSearch not available: %w", err)
+	}
+	s.useIndex = true
+
+	if err := s.redis.client.Do(ctx, "FT.INFO", vectorIndexName).Err(); err == nil {
+		s.indexMu.Lock()
+		s.indexReady = true
+		s.indexMu.Unlock()
+		log.Printf("🧭 기존 벡터 인덱스 사용: %s", vectorIndexName)
+	}
+	return nil
+}
+
+// Store는 쿼리 임베딩을 저장 (응답 자체는 RedisClient.Set으로 저장된 항목을 참조)
+// ttl은 응답 항목과 같은 값을 사용 (stale 보관 기간 포함)
+func (s *SemanticCache) Store(ctx context.Context, query string, ttl time.Duration) error {
+	vector, err := s.embedder.Embed(ctx, query)
+	if err != nil {
+		return fmt.Errorf("embed query failed: %w", err)
+	}
+
+	hash := strings.TrimPrefix(s.redis.Key(query), chatKeyPrefix)
+	ttl += s.redis.staleGrace
+
+	if s.useIndex {
+		if err := s.ensureIndex(ctx, len(vector)); err != nil {
+			return err
+		}
+
+		key := vectorKeyPrefix + hash
+		pipe := s.redis.client.TxPipeline()
+		pipe.HSet(ctx, key, "query", query, "vector", encodeVector(vector))
+		pipe.Expire(ctx, key, ttl)
+		_, err := pipe.Exec(ctx)
+		return err
+	}
+
+	data, err := json.Marshal(semanticEntry{Query: query, Vector: vector})
+	if err != nil {
+		return err
+	}
+	return s.redis.client.Set(ctx, semanticKeyPrefix+hash, data, ttl).Err()
+}
+
+// FindSimilar는 임계값 이상으로 가장 유사한 캐시 항목 조회 (없으면 nil)
+func (s *SemanticCache) FindSimilar(ctx context.Context, query string) (*SemanticMatch, error) {
+	matches, err := s.Search(ctx, query, 1)
+	if err != nil || len(matches) == 0 {
+		return nil, err
+	}
+	return &matches[0], nil
+}
+
+// Search는 임계값 이상인 유사 질문을 유사도 내림차순으로 최대 k개 조회
+// 응답이 이미 만료된 후보는 제외
+func (s *SemanticCache) Search(ctx context.Context, query string, k int) ([]SemanticMatch, error) {
+	vector, err := s.embedder.Embed(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("embed query failed: %w", err)
+	}
+
+	var candidates []scoredQuery
+	if s.useIndex {
+		candidates, err = s.searchIndex(ctx, vector)
+	} else {
+		candidates, err = s.searchBruteForce(ctx, vector)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []SemanticMatch
+	for _, candidate := range candidates {
+		if candidate.score < s.threshold {
+			continue
+		}
+
+		cached, err := s.redis.Get(candidate.query)
+		if err != nil || cached == nil {
+			continue
+		}
+
+		matches = append(matches, SemanticMatch{
+			Key:      s.redis.Key(candidate.query),
+			Score:    candidate.score,
+			Response: cached,
+		})
+		if len(matches) == k {
+			break
+		}
+	}
+	return matches, nil
+}
+
+// scoredQuery는 유사도가 계산된 후보 질문
+type scoredQuery struct {
+	query string
+	score float64
+}
+
+// searchBruteForce는 모든 임베딩을 읽어 Go에서 코사인 유사도를 계산 (RediSearch 미사용 시)
+func (s *SemanticCache) searchBruteForce(ctx context.Context, vector []float32) ([]scoredQuery, error) {
+	var candidates []scoredQuery
+
+	iter := s.redis.client.Scan(ctx, 0, semanticKeyPrefix+"*", 1000).Iterator()
+	for iter.Next(ctx) {
+		data, err := s.redis.client.Get(ctx, iter.Val()).Bytes()
+		if err != nil {
+			continue
+		}
+
+		var entry semanticEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			continue
+		}
+
+		candidates = append(candidates, scoredQuery{
+			query: entry.Query,
+			score: embedding.CosineSimilarity(vector, entry.Vector),
+		})
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+	if len(candidates) > semanticTopK {
+		candidates = candidates[:semanticTopK]
+	}
+	return candidates, nil
+}
+
+// searchIndex는 RediSearch KNN 쿼리로 상위 후보 조회
+// FT.SEARCH는 코사인 거리(1 - 유사도)를 반환하므로 유사도로 변환
+func (s *SemanticCache) searchIndex(ctx context.Context, vector []float32) ([]scoredQuery, error) {
+	s.indexMu.Lock()
+	ready := s.indexReady
+	s.indexMu.Unlock()
+	if !ready {
+		return nil, nil // 아직 저장된 임베딩 없음
+	}
+
+	res, err := s.redis.client.Do(ctx, "FT.SEARCH", vectorIndexName,
+		fmt.Sprintf("*=>[KNN %d @vector $vec AS distance]", semanticTopK),
+		"PARAMS", "2", "vec", encodeVector(vector),
+		"SORTBY", "distance",
+		"RETURN", "2", "query", "distance",
+		"DIALECT", "2",
+	).Result()
+	if err != nil {
+		return nil, fmt.Errorf("vector search failed: %w", err)
+	}
+
+	// 응답 형식: [총 개수, 키1, [필드, 값, ...], 키2, [...], ...]
+	rows, ok := res.([]any)
+	if !ok || len(rows) < 1 {
+		return nil, nil
+	}
+
+	var candidates []scoredQuery
+	for i := 2; i < len(rows); i += 2 {
+		fields, ok := rows[i].([]any)
+		if !ok {
+			continue
+		}
+
+		var candidate scoredQuery
+		for j := 0; j+1 < len(fields); j += 2 {
+			name, _ := fields[j].(string)
+			value, _ := fields[j+1].(string)
+			switch name {
+			case "query":
+				candidate.query = value
+			case "distance":
+				distance, err := strconv.ParseFloat(value, 64)
+				if err != nil {
+					continue
+				}
+				candidate.score = 1 - distance
+			}
+		}
+		if candidate.query != "" {
+			candidates = append(candidates, candidate)
+		}
+	}
+	return candidates, nil
+}
+
+// ensureIndex는 벡터 인덱스가 없으면 생성 (임베딩 차원은 첫 저장 시 결정)
+func (s *SemanticCache) ensureIndex(ctx context.Context, dims int) error {
+	s.indexMu.Lock()
+	defer s.indexMu.Unlock()
+
+	if s.indexReady {
+		return nil
+	}
+
+	err := s.redis.client.Do(ctx, "FT.CREATE", vectorIndexName,
+		"ON", "HASH", "PREFIX", "1", vectorKeyPrefix,
+		"SCHEMA",
+		"query", "TEXT",
+		"vector", "VECTOR", "FLAT", "6",
+		"TYPE", "FLOAT32", "DIM", strconv.Itoa(dims), "DISTANCE_METRIC", "COSINE",
+	).Err()
+	if err != nil && !strings.Contains(err.Error(), "Index already exists") {
+		return fmt.Errorf("create vector index failed: %w", err)
+	}
+
+	if err == nil {
+		log.Printf("🧭 벡터 인덱스 생성: %s (dim=%d)", vectorIndexName, dims)
+	}
+	s.indexReady = true
+	return nil
+}
+
+// encodeVector는 벡터를 RediSearch FLOAT32 형식(리틀 엔디언 바이트)으로 인코딩
+func encodeVector(vector []float32) string {
+	buf := make([]byte, 4*len(vector))
+	for i, v := range vector {
+		binary.LittleEndian.PutUint32(buf[i*4:], math.Float32bits(v))
+	}
+	return string(buf)
+}
@@ -0,0 +1,205 @@
+package cache
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// 캐시 항목 직렬화 형식 (CACHE_SERIALIZER)
+// msgpack은 외부 의존성이 필요해 지원하지 않고, 같은 목적(필드 이름 제거, 빠른 인코딩)의 binary 형식을 표준 라이브러리로 제공
+const (
+	SerializerJSON   = "json"   // 기본값, 사람이 읽을 수 있고 redis-cli로 확인하기 쉬움
+	SerializerBinary = "binary" // 필드 이름 없이 길이 접두사로 기록하는 압축 형식 (표준 라이브러리만 사용)
+)
+
+// binaryMarker는 binary 형식 값의 첫 바이트 (JSON 값은 항상 '{'로 시작하므로 구분 가능)
+// 다음 바이트는 형식 버전
+const (
+	binaryMarker  = 0x01
+	binaryVersion = 1
+)
+
+// binary 형식 플래그
+const (
+	binaryFlagNegative = 1 << iota
+)
+
+// errCorruptValue는 binary 형식 값을 끝까지 해석할 수 없을 때의 에러
+var errCorruptValue = errors.New("corrupt binary cache value")
+
+// Serializer는 채팅 캐시 항목(CachedResponse)을 Redis 값으로 변환하는 형식
+// 저장은 설정한 형식으로 하고, 조회는 값의 첫 바이트로 형식을 판별하므로 형식을 바꿔도 기존 항목을 그대로 읽는다.
+type Serializer interface {
+	Name() string
+	Marshal(cached *CachedResponse) ([]byte, error)
+	Unmarshal(data []byte, cached *CachedResponse) error
+}
+
+// NewSerializer는 CACHE_SERIALIZER 값에 해당하는 Serializer 반환
+func NewSerializer(name string) (Serializer, error) {
+	switch name {
+	case "", SerializerJSON:
+		return jsonSerializer{}, nil
+	case SerializerBinary:
+		return binarySerializer{}, nil
+	}
+	return nil, fmt.Errorf("unknown cache serializer %q (json, binary)", name)
+}
+
+// decodeCachedResponse는 값의 첫 바이트로 저장 형식을 판별해 캐시 항목 복원
+func decodeCachedResponse(data []byte) (*CachedResponse, error) {
+	var serializer Serializer = jsonSerializer{}
+	if len(data) > 0 && data[0] == binaryMarker {
+		serializer = binarySerializer{}
+	}
+
+	var cached CachedResponse
+	if err := serializer.Unmarshal(data, &cached); err != nil {
+		return nil, err
+	}
+	return &cached, nil
+}
+
+// jsonSerializer는 기존 JSON 형식
+type jsonSerializer struct{}
+
+func (jsonSerializer) Name() string { return SerializerJSON }
+
+func (jsonSerializer) Marshal(cached *CachedResponse) ([]byte, error) {
+	return json.Marshal(cached)
+}
+
+func (jsonSerializer) Unmarshal(data []byte, cached *CachedResponse) error {
+	return json.Unmarshal(data, cached)
+}
+
+// binarySerializer는 marker, 버전, 플래그 뒤에 필드를 고정 순서로 기록하는 형식
+// 문자열은 uvarint 길이 + 바이트, 시각은 UnixNano varint (0이면 zero time), 상태 코드는 varint
+type binarySerializer struct{}
+
+func (binarySerializer) Name() string { return SerializerBinary }
+
+func (binarySerializer) Marshal(cached *CachedResponse) ([]byte, error) {
+	// 헤더 3바이트 + 길이/시각/상태/청크 수 varint 7개 + 문자열 본문
+	size := 3 + 7*binary.MaxVarintLen64 + len(cached.Query) + len(cached.Response) + len(cached.Version)
+	for _, chunk := range cached.Chunks {
+		size += len(chunk) + binary.MaxVarintLen32
+	}
+	buf := make([]byte, 0, size)
+
+	var flags byte
+	if cached.Negative {
+		flags |= binaryFlagNegative
+	}
+	buf = append(buf, binaryMarker, binaryVersion, flags)
+	buf = appendString(buf, cached.Query)
+	buf = appendString(buf, cached.Response)
+	buf = binary.AppendVarint(buf, unixNano(cached.CreatedAt))
+	buf = binary.AppendVarint(buf, unixNano(cached.ExpiresAt))
+	buf = binary.AppendVarint(buf, int64(cached.Status))
+	buf = appendString(buf, cached.Version)
+	buf = binary.AppendUvarint(buf, uint64(len(cached.Chunks)))
+	for _, chunk := range cached.Chunks {
+		buf = appendString(buf, chunk)
+	}
+	return buf, nil
+}
+
+func (binarySerializer) Unmarshal(data []byte, cached *CachedResponse) error {
+	if len(data) < 3 {
+		return errCorruptValue
+	}
+	if data[1] != binaryVersion {
+		return fmt.Errorf("unsupported binary cache value version %d", data[1])
+	}
+	cached.Negative = data[2]&binaryFlagNegative != 0
+
+	d := binaryDecoder{data: data[3:]}
+	cached.Query = d.string()
+	cached.Response = d.string()
+	cached.CreatedAt = fromUnixNano(d.varint())
+	cached.ExpiresAt = fromUnixNano(d.varint())
+	cached.Status = int(d.varint())
+	cached.Version = d.string()
+	if n := d.uvarint(); n > 0 && d.err == nil {
+		if n > uint64(len(d.data)) {
+			return errCorruptValue
+		}
+		cached.Chunks = make([]string, n)
+		for i := range cached.Chunks {
+			cached.Chunks[i] = d.string()
+		}
+	}
+	return d.err
+}
+
+// appendString은 uvarint 길이와 문자열 바이트 추가
+func appendString(buf []byte, s string) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(s)))
+	return append(buf, s...)
+}
+
+// unixNano는 시각을 UnixNano로 변환 (zero time은 0)
+func unixNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+// fromUnixNano는 unixNano의 역변환
+func fromUnixNano(n int64) time.Time {
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, n)
+}
+
+// binaryDecoder는 binary 형식 필드를 순서대로 읽음 (처음 발생한 에러를 보관하고 이후 읽기는 빈 값)
+type binaryDecoder struct {
+	data []byte
+	err  error
+}
+
+func (d *binaryDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.err = errCorruptValue
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+func (d *binaryDecoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.data)
+	if n <= 0 {
+		d.err = errCorruptValue
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+func (d *binaryDecoder) string() string {
+	n := d.uvarint()
+	if d.err != nil {
+		return ""
+	}
+	if n > uint64(len(d.data)) {
+		d.err = errCorruptValue
+		return ""
+	}
+	s := string(d.data[:n])
+	d.data = d.data[n:]
+	return s
+}
@@ -0,0 +1,175 @@
+package cache
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sampleResponse는 한국어 답변 약 2KB 항목 (chunks > 0이면 답변을 그 수만큼 나눈 SSE 청크 포함)
+func sampleResponse(chunks int) *CachedResponse {
+	answer := strings.Repeat("RAG 시스템은 검색한 문서를 근거로 답변을 생성합니다. ", 28)
+	created := time.Date(2026, 10, 15, 9, 0, 0, 123456789, time.UTC)
+	cached := &CachedResponse{
+		Query:     "RAG 시스템은 어떻게 동작하나요?",
+		Response:  answer,
+		CreatedAt: created,
+		ExpiresAt: created.Add(time.Hour),
+		Version:   "v2",
+	}
+	if chunks > 0 {
+		runes := []rune(answer)
+		size := (len(runes) + chunks - 1) / chunks
+		for start := 0; start < len(runes); start += size {
+			cached.Chunks = append(cached.Chunks, string(runes[start:min(start+size, len(runes))]))
+		}
+	}
+	return cached
+}
+
+// equalCached는 시각을 Equal로 비교 (역직렬화한 시각은 위치/단조 시계 정보가 다를 수 있음)
+func equalCached(t *testing.T, got, want *CachedResponse) {
+	t.Helper()
+	if !got.CreatedAt.Equal(want.CreatedAt) || !got.ExpiresAt.Equal(want.ExpiresAt) {
+		t.Errorf("times = %v/%v, want %v/%v", got.CreatedAt, got.ExpiresAt, want.CreatedAt, want.ExpiresAt)
+	}
+	if got.CreatedAt.IsZero() != want.CreatedAt.IsZero() || got.ExpiresAt.IsZero() != want.ExpiresAt.IsZero() {
+		t.Error("zero time not preserved")
+	}
+	g, w := *got, *want
+	g.CreatedAt, g.ExpiresAt, w.CreatedAt, w.ExpiresAt = time.Time{}, time.Time{}, time.Time{}, time.Time{}
+	if !reflect.DeepEqual(g, w) {
+		t.Errorf("got %+v, want %+v", g, w)
+	}
+}
+
+func TestSerializerRoundTrip(t *testing.T) {
+	entries := map[string]*CachedResponse{
+		"answer": sampleResponse(0),
+		"chunks": sampleResponse(150),
+		"negative": {
+			Query: "없는 문서", Response: `{"detail":"not found"}`, Negative: true, Status: 404,
+			CreatedAt: time.Unix(1760000000, 0), ExpiresAt: time.Unix(1760000060, 0),
+		},
+		"empty negative answer": {Query: "빈 답변", Negative: true, Status: 200, CreatedAt: time.Unix(1760000000, 0)},
+		"zero times":            {Query: "시각 없음", Response: "답변"},
+		"empty":                 {},
+		"empty chunk":           {Query: "q", Response: "a", Chunks: []string{"a", ""}},
+	}
+	for _, name := range []string{SerializerJSON, SerializerBinary} {
+		serializer, err := NewSerializer(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for entryName, want := range entries {
+			t.Run(name+"/"+entryName, func(t *testing.T) {
+				data, err := serializer.Marshal(want)
+				if err != nil {
+					t.Fatal(err)
+				}
+				// 조회는 형식을 지정하지 않고 값으로 판별
+				got, err := decodeCachedResponse(data)
+				if err != nil {
+					t.Fatal(err)
+				}
+				equalCached(t, got, want)
+			})
+		}
+	}
+}
+
+func TestNewSerializer(t *testing.T) {
+	for name, want := range map[string]string{"": SerializerJSON, "json": SerializerJSON, "binary": SerializerBinary} {
+		serializer, err := NewSerializer(name)
+		if err != nil || serializer.Name() != want {
+			t.Errorf("NewSerializer(%q) = %v, %v, want %s", name, serializer, err, want)
+		}
+	}
+	for _, name := range []string{"msgpack", "JSON", "gob"} {
+		if _, err := NewSerializer(name); err == nil {
+			t.Errorf("NewSerializer(%q): expected error", name)
+		}
+	}
+}
+
+func TestBinarySerializerRejectsTruncatedValues(t *testing.T) {
+	data, err := binarySerializer{}.Marshal(sampleResponse(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 어느 위치에서 잘려도 일부 필드만 채운 항목을 반환하지 않아야 함
+	for n := 1; n < len(data); n++ {
+		if _, err := decodeCachedResponse(data[:n]); err == nil {
+			t.Fatalf("truncated to %d of %d bytes: expected error", n, len(data))
+		}
+	}
+}
+
+func TestBinarySerializerRejectsCorruptValues(t *testing.T) {
+	valid, err := binarySerializer{}.Marshal(&CachedResponse{Query: "q", Response: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string][]byte{
+		"marker only":          {binaryMarker},
+		"unknown version":      append([]byte{binaryMarker, binaryVersion + 1}, valid[2:]...),
+		"string length beyond": {binaryMarker, binaryVersion, 0, 0x7f, 'q'},
+		"unterminated varint":  {binaryMarker, binaryVersion, 0, 0xff, 0xff},
+		"chunk count beyond":   append(append([]byte{}, valid[:len(valid)-1]...), 0x7f),
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := decodeCachedResponse(data); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+
+	if _, err := decodeCachedResponse([]byte{binaryMarker}); !errors.Is(err, errCorruptValue) {
+		t.Errorf("err = %v, want errCorruptValue", err)
+	}
+	if _, err := decodeCachedResponse([]byte(`{"query":`)); err == nil {
+		t.Error("truncated JSON: expected error")
+	}
+}
+
+// benchmarkSerializers는 형식별 직렬화/역직렬화 벤치마크 (값 크기를 bytes/value 지표로 보고)
+func benchmarkSerializers(b *testing.B, cached *CachedResponse) {
+	for _, name := range []string{SerializerJSON, SerializerBinary} {
+		serializer, _ := NewSerializer(name)
+		data, err := serializer.Marshal(cached)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run(name+"/marshal", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := serializer.Marshal(cached); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(len(data)), "bytes/value")
+		})
+		b.Run(name+"/unmarshal", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				var out CachedResponse
+				if err := serializer.Unmarshal(data, &out); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(len(data)), "bytes/value")
+		})
+	}
+}
+
+func BenchmarkSerializerAnswer(b *testing.B) {
+	benchmarkSerializers(b, sampleResponse(0))
+}
+
+func BenchmarkSerializerChunks(b *testing.B) {
+	benchmarkSerializers(b, sampleResponse(150))
+}
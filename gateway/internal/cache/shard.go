@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"context"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// chatKeyPrefix는 채팅 캐시 키 접두사
+const chatKeyPrefix = "chat:"
+
+// shardKey는 CACHE_SHARDS가 2 이상이면 해시 앞 2바이트로 버킷을 정해 "chat:{버킷}:{해시}" 키 반환
+// 1 이하면 기존과 같은 "chat:{해시}" 그대로
+func shardKey(key string, shards int) string {
+	if shards <= 1 {
+		return key
+	}
+	hash := strings.TrimPrefix(key, chatKeyPrefix)
+	prefix, err := hex.DecodeString(hash[:4])
+	if err != nil {
+		return key
+	}
+	bucket := (int(prefix[0])<<8 | int(prefix[1])) % shards
+	return chatKeyPrefix + strconv.Itoa(bucket) + ":" + hash
+}
+
+// SetShards는 채팅 캐시 키를 나눌 논리 버킷 수 설정 (트래픽 처리 시작 전에 호출)
+// 버킷 수를 바꾸면 키가 달라지므로 기존 항목은 미스가 되고 TTL로 정리된다.
+func (r *RedisClient) SetShards(shards int) {
+	r.keys.shards = shards
+}
+
+// scanPatterns는 채팅 캐시 키 SCAN 패턴 목록 (버킷마다 하나와 클라이언트 지정 키, 버킷이 없으면 chat:* 하나)
+func (r *RedisClient) scanPatterns() []string {
+	if r.keys.shards <= 1 {
+		return []string{chatKeyPrefix + "*"}
+	}
+	patterns := make([]string, r.keys.shards, r.keys.shards+1)
+	for i := range patterns {
+		patterns[i] = chatKeyPrefix + strconv.Itoa(i) + ":*"
+	}
+	return append(patterns, clientKeyPrefix+"*")
+}
+
+// scanKeys는 버킷별 SCAN을 동시에 실행해 채팅 캐시 키를 모음
+// collect가 false면 키는 모으지 않고 개수만 반환 (Count)
+func (r *RedisClient) scanKeys(ctx context.Context, collect bool) ([]string, int, error) {
+	patterns := r.scanPatterns()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		keys     []string
+		count    int
+		firstErr error
+	)
+	for _, pattern := range patterns {
+		wg.Add(1)
+		go func(pattern string) {
+			defer wg.Done()
+
+			var found []string
+			n := 0
+			iter := r.client.Scan(ctx, 0, pattern, 1000).Iterator()
+			for iter.Next(ctx) {
+				n++
+				if collect {
+					found = append(found, iter.Val())
+				}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err := iter.Err(); err != nil && firstErr == nil {
+				firstErr = err
+			}
+			keys = append(keys, found...)
+			count += n
+		}(pattern)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, 0, firstErr
+	}
+	return keys, count, nil
+}
@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"context"
+	"math/rand"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// 항목 크기 집계 방식 (CACHE_SIZE_STATS)
+const (
+	SizeStatsOff    = "off"    // 집계하지 않음
+	SizeStatsSample = "sample" // 무작위 표본의 MEMORY USAGE로 전체 추정
+	SizeStatsFull   = "full"   // 모든 chat:* 키에 MEMORY USAGE (키가 많으면 느림)
+)
+
+// sizeStatsConfig는 GetStats의 항목 크기 집계 설정
+type sizeStatsConfig struct {
+	method string
+	sample int
+}
+
+// SetSizeStats는 GetStats에서 chat:* 항목 크기를 집계할 방식과 표본 크기 설정
+func (r *RedisClient) SetSizeStats(method string, sample int) {
+	r.sizeStats = sizeStatsConfig{method: method, sample: sample}
+}
+
+// entrySizes는 chat:* 항목의 MEMORY USAGE 통계 (바이트)
+// sample 방식이면 표본 평균에 전체 키 수를 곱해 total_bytes를 추정
+func (r *RedisClient) entrySizes(ctx context.Context, keys []string) (map[string]any, error) {
+	method := r.sizeStats.method
+	targets := keys
+	if method == SizeStatsSample && len(keys) > r.sizeStats.sample {
+		targets = make([]string, r.sizeStats.sample)
+		for i, idx := range rand.Perm(len(keys))[:r.sizeStats.sample] {
+			targets[i] = keys[idx]
+		}
+	}
+
+	pipe := r.client.Pipeline()
+	cmds := make([]*redis.IntCmd, len(targets))
+	for i, key := range targets {
+		cmds[i] = pipe.MemoryUsage(ctx, key)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	var measured, total, largest int64
+	for _, cmd := range cmds {
+		size, err := cmd.Result()
+		if err != nil {
+			continue // 집계 도중 만료된 키
+		}
+		measured++
+		total += size
+		largest = max(largest, size)
+	}
+
+	stats := map[string]any{
+		"method":      method,
+		"sampled":     measured,
+		"total_bytes": total,
+		"avg_bytes":   int64(0),
+		"max_bytes":   largest,
+	}
+	if measured > 0 {
+		avg := total / measured
+		stats["avg_bytes"] = avg
+		if len(targets) < len(keys) {
+			stats["total_bytes"] = avg * int64(len(keys))
+			stats["estimated"] = true
+		}
+	}
+	return stats, nil
+}
@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// clientKeyMarker는 클라이언트가 지정한 캐시 키(X-Cache-Key)를 쿼리 대신 전달할 때 붙이는 표시
+// 환경 변수나 일반 쿼리 정규화 결과에는 나올 수 없는 NUL 문자로 시작한다.
+const clientKeyMarker = "\x00client-key\x00"
+
+// clientKeyPrefix는 클라이언트 지정 캐시 키의 접두사 (자동 생성 키 chat:{hash}와 겹치지 않음)
+const clientKeyPrefix = chatKeyPrefix + "client:"
+
+// ClientKeyQuery는 클라이언트 지정 캐시 키를 Cache 메서드의 query 인자로 사용할 값으로 변환
+// 이 값으로 조회/저장하면 쿼리 정규화, 분류, 버킷 없이 "chat:client:{키}" 공간을 사용한다.
+func ClientKeyQuery(key string) string {
+	return clientKeyMarker + key
+}
+
+// tenantMarker는 테넌트별 키 공간(CACHE_TENANT)을 쿼리 앞에 붙여 전달할 때의 표시 (NUL로 테넌트와 쿼리 구분)
+const tenantMarker = "\x00tenant\x00"
+
+// TenantQuery는 테넌트 식별자를 Cache 메서드의 query 인자에 포함
+// 이 값으로 조회/저장하면 같은 질문이라도 테넌트마다 다른 키 공간을 사용한다 (클라이언트 지정 키도 테넌트별로 분리).
+func TenantQuery(tenant, query string) string {
+	return tenantMarker + tenant + "\x00" + query
+}
+
+// keySpace는 채팅 캐시 키에 섞는 버전(CACHE_VERSION)과 쿼리 분류, 키 버킷(CACHE_SHARDS)
+// 버전을 바꾸면 기존 항목은 키가 달라져 미스가 되고 TTL로 자연히 정리된다.
+type keySpace struct {
+	version atomic.Value // string
+
+	// scope는 쿼리 분류(QUERY_ROUTES_FILE) 결과를 키에 포함 (시작 시 한 번 설정)
+	// 분류마다 다른 Backend가 답하므로 같은 질문이라도 분류가 다르면 다른 키를 사용
+	scope func(query string) string
+
+	// shards는 키를 나눌 논리 버킷 수 (CACHE_SHARDS, 1 이하면 나누지 않음, 시작 시 한 번 설정)
+	shards int
+}
+
+// get은 현재 버전 반환 (설정 전이면 빈 문자열)
+func (k *keySpace) get() string {
+	v, _ := k.version.Load().(string)
+	return v
+}
+
+// set은 버전 변경 (실행 중 변경 가능)
+func (k *keySpace) set(version string) {
+	k.version.Store(version)
+}
+
+// key는 주어진 버전과 쿼리 분류, 테넌트를 적용한 캐시 키 반환
+// 클라이언트 지정 키(ClientKeyQuery)는 "chat:client:{버전:}{테넌트/}{키}" 그대로 사용
+func (k *keySpace) key(version, query string) string {
+	tenant := ""
+	if rest, ok := strings.CutPrefix(query, tenantMarker); ok {
+		tenant, query, _ = strings.Cut(rest, "\x00")
+	}
+
+	if clientKey, ok := strings.CutPrefix(query, clientKeyMarker); ok {
+		if tenant != "" {
+			clientKey = tenant + "/" + clientKey
+		}
+		if version != "" {
+			return clientKeyPrefix + version + ":" + clientKey
+		}
+		return clientKeyPrefix + clientKey
+	}
+
+	namespace := version
+	if k.scope != nil {
+		if category := k.scope(query); category != "" {
+			namespace += "/" + category
+		}
+	}
+	if tenant != "" {
+		namespace += "@" + tenant
+	}
+	return shardKey(generateCacheKey(namespace, query), k.shards)
+}
@@ -0,0 +1,181 @@
+package cache
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// 쓰기 큐가 가득 찼을 때 동작 (CACHE_WRITE_QUEUE_FULL)
+const (
+	WriteQueueDrop  = "drop"  // 저장을 버리고 바로 반환 (응답 지연 없음)
+	WriteQueueBlock = "block" // 큐에 자리가 날 때까지 대기
+)
+
+// ErrWriteQueueFull은 write-behind 큐가 가득 차 저장을 버렸을 때 반환
+var ErrWriteQueueFull = errors.New("cache write queue full")
+
+// writeQueueMaxWait는 block 모드에서 큐에 자리가 나기를 기다리는 최대 시간
+// Redis가 멈춰 큐가 비워지지 않아도 응답 경로가 무한정 묶이지 않도록, 넘으면 drop처럼 저장을 버린다.
+const writeQueueMaxWait = time.Second
+
+// pendingWrite는 큐에 쌓인 Redis SET 하나
+type pendingWrite struct {
+	key   string
+	data  []byte
+	ttl   time.Duration
+	touch bool // LRU 접근 시각 기록 대상 (채팅 항목)
+}
+
+// writeBehind는 캐시 저장을 큐에 모았다가 백그라운드에서 파이프라인으로 한 번에 기록
+// 응답 경로에서 Redis 왕복을 없애는 대신 저장이 최대 flushInterval만큼 늦게 반영된다.
+type writeBehind struct {
+	client        *RedisClient
+	queue         chan pendingWrite
+	batchSize     int
+	flushInterval time.Duration
+	block         bool
+
+	mu        sync.RWMutex // closed와 큐 닫기 보호 (닫힌 큐에 보내지 않도록)
+	closed    bool
+	closing   chan struct{} // 종료 시작 시 닫힘 (block 모드에서 대기 중인 저장을 깨워 close가 잠금을 얻도록)
+	closeOnce sync.Once
+	done      chan struct{}
+	dropped   atomic.Int64
+	flushed   atomic.Int64
+}
+
+// EnableWriteBehind는 write-behind 저장 모드 활성화 (트래픽 처리 시작 전에 호출)
+// queueSize: 큐 용량, batchSize: 파이프라인 한 번에 기록할 최대 수, flushInterval: 배치가 덜 찼어도 기록하는 주기
+// onFull이 WriteQueueBlock이면 큐가 찼을 때 대기, 아니면 버림. 남은 항목은 Close에서 모두 기록한다.
+func (r *RedisClient) EnableWriteBehind(queueSize, batchSize int, flushInterval time.Duration, onFull string) {
+	wb := &writeBehind{
+		client:        r,
+		queue:         make(chan pendingWrite, max(queueSize, 1)),
+		batchSize:     max(batchSize, 1),
+		flushInterval: flushInterval,
+		block:         onFull == WriteQueueBlock,
+		closing:       make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+	if wb.flushInterval <= 0 {
+		wb.flushInterval = 10 * time.Millisecond
+	}
+	r.writer = wb
+	go wb.run()
+}
+
+// enqueue는 SET 하나를 큐에 추가
+// 닫힌 뒤거나, 큐가 찼을 때 drop 모드이거나 block 모드에서 writeQueueMaxWait 안에 자리가 나지 않으면 ErrWriteQueueFull
+func (wb *writeBehind) enqueue(write pendingWrite) error {
+	wb.mu.RLock()
+	defer wb.mu.RUnlock()
+	if wb.closed {
+		return ErrWriteQueueFull
+	}
+
+	select {
+	case wb.queue <- write:
+		return nil
+	default:
+	}
+	if wb.block {
+		timer := time.NewTimer(writeQueueMaxWait)
+		defer timer.Stop()
+		select {
+		case wb.queue <- write:
+			return nil
+		case <-timer.C:
+		case <-wb.closing:
+		}
+	}
+	wb.dropped.Add(1)
+	return ErrWriteQueueFull
+}
+
+// run은 큐를 읽어 batchSize가 차거나 flushInterval이 지나면 기록 (큐가 닫히면 남은 항목을 기록하고 종료)
+func (wb *writeBehind) run() {
+	defer close(wb.done)
+
+	ticker := time.NewTicker(wb.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]pendingWrite, 0, wb.batchSize)
+	for {
+		select {
+		case write, ok := <-wb.queue:
+			if !ok {
+				wb.flush(batch)
+				return
+			}
+			batch = append(batch, write)
+			if len(batch) >= wb.batchSize {
+				wb.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				wb.flush(batch)
+				batch = batch[:0]
+			}
+		}
+	}
+}
+
+// flush는 배치를 Redis 파이프라인 한 번으로 기록 (명령별 결과는 SET 지표에 반영)
+func (wb *writeBehind) flush(batch []pendingWrite) {
+	if len(batch) == 0 {
+		return
+	}
+
+	r := wb.client
+	start := time.Now()
+	cmds := make([]*redis.StatusCmd, len(batch))
+	_, err := r.client.Pipelined(r.ctx, func(pipe redis.Pipeliner) error {
+		for i, write := range batch {
+			cmds[i] = pipe.Set(r.ctx, write.key, write.data, write.ttl)
+			if write.touch && r.lru.Load() {
+				pipe.ZAdd(r.ctx, accessSetKey, &redis.Z{Score: float64(start.Unix()), Member: write.key})
+			}
+		}
+		return nil
+	})
+	for _, cmd := range cmds {
+		r.metrics.observe(opSet, start, cmd.Err())
+	}
+	if err != nil {
+		log.Printf("⚠️ 캐시 배치 저장 실패 (%d개): %v", len(batch), err)
+		return
+	}
+	wb.flushed.Add(int64(len(batch)))
+}
+
+// close는 새 저장을 막고 큐에 남은 항목을 모두 기록할 때까지 대기
+func (wb *writeBehind) close() {
+	wb.closeOnce.Do(func() { close(wb.closing) })
+	wb.mu.Lock()
+	if wb.closed {
+		wb.mu.Unlock()
+		return
+	}
+	wb.closed = true
+	close(wb.queue)
+	wb.mu.Unlock()
+
+	<-wb.done
+	log.Printf("💾 캐시 쓰기 큐 비움 (기록 %d개, 버림 %d개)", wb.flushed.Load(), wb.dropped.Load())
+}
+
+// stats는 /api/cache/stats용 큐 상태
+func (wb *writeBehind) stats() map[string]any {
+	return map[string]any{
+		"queued":   len(wb.queue),
+		"capacity": cap(wb.queue),
+		"flushed":  wb.flushed.Load(),
+		"dropped":  wb.dropped.Load(),
+	}
+}
@@ -0,0 +1,81 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// fullQueue는 용량 1짜리 큐를 채운 block 모드 writeBehind 생성 (run 고루틴 없이 큐가 비워지지 않음)
+func fullQueue(t *testing.T) *writeBehind {
+	t.Helper()
+	wb := &writeBehind{
+		queue:   make(chan pendingWrite, 1),
+		block:   true,
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	if err := wb.enqueue(pendingWrite{key: "first"}); err != nil {
+		t.Fatal(err)
+	}
+	return wb
+}
+
+func TestWriteBehindBlockModeBoundedWait(t *testing.T) {
+	wb := fullQueue(t)
+
+	start := time.Now()
+	err := wb.enqueue(pendingWrite{key: "second"})
+	if !errors.Is(err, ErrWriteQueueFull) {
+		t.Fatalf("err = %v, want ErrWriteQueueFull", err)
+	}
+	if waited := time.Since(start); waited < writeQueueMaxWait || waited > writeQueueMaxWait+time.Second {
+		t.Errorf("waited %v, want about %v", waited, writeQueueMaxWait)
+	}
+	if n := wb.dropped.Load(); n != 1 {
+		t.Errorf("dropped = %d, want 1", n)
+	}
+}
+
+func TestWriteBehindBlockModeWakesOnClose(t *testing.T) {
+	wb := fullQueue(t)
+
+	result := make(chan error, 1)
+	go func() { result <- wb.enqueue(pendingWrite{key: "second"}) }()
+	time.Sleep(20 * time.Millisecond)
+
+	// 종료 시작: 대기 중인 저장이 잠금을 놓아야 close가 진행할 수 있음
+	wb.closeOnce.Do(func() { close(wb.closing) })
+	select {
+	case err := <-result:
+		if !errors.Is(err, ErrWriteQueueFull) {
+			t.Errorf("err = %v, want ErrWriteQueueFull", err)
+		}
+	case <-time.After(writeQueueMaxWait / 2):
+		t.Fatal("blocked enqueue not woken by close")
+	}
+
+	locked := make(chan struct{})
+	go func() {
+		wb.mu.Lock()
+		close(locked)
+		wb.mu.Unlock()
+	}()
+	select {
+	case <-locked:
+	case <-time.After(time.Second):
+		t.Fatal("close could not acquire the lock")
+	}
+}
+
+func TestWriteBehindBlockModeWaitsForSpace(t *testing.T) {
+	wb := fullQueue(t)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		<-wb.queue
+	}()
+	if err := wb.enqueue(pendingWrite{key: "second"}); err != nil {
+		t.Errorf("err = %v, want queued after space frees up", err)
+	}
+}
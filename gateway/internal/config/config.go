@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -12,25 +13,269 @@ import (
 // Config는 Gateway 설정을 담는 구조체
 type Config struct {
 	// 서버 설정
-	Port string
+	Port           string
+	MaxConnections int // 동시 TCP 연결 수 상한 (0이면 무제한)
+
+	// http.Server 타임아웃 (초 단위, 0이면 제한 없음)
+	ServerReadHeaderTimeout int
+	ServerReadTimeout       int
+	ServerWriteTimeout      int // SSE 스트림은 쓰기 데드라인을 해제하므로 적용되지 않음
+	ServerIdleTimeout       int
+
+	// 종료 신호 후 진행 중인 요청(SSE 스트림 포함)을 기다리는 최대 시간 (초, 지나면 남은 연결을 끊음)
+	ShutdownTimeout int
+
+	// 전역 요청 타임아웃 (스트리밍 경로 제외, 0이면 비활성화)
+	GlobalTimeout       int // 초 단위
+	GlobalTimeoutBypass []string
 
 	// Backend 설정
-	BackendURL string
+	BackendURL          string
+	BackendURLs         []string // 가중치 부하 분산 대상 ("http://a:8081=3,http://b:8081=1", 비어 있으면 BackendURL만 사용)
+	BackendDownCooldown int      // 연결 실패한 Backend를 부하 분산에서 제외하는 기간 (초 단위)
+	BackendDrainTimeout int      // 드레이닝 중 진행 중인 요청을 기다리는 최대 시간 (초 단위)
+	ProxyFlushInterval  int      // 리버스 프록시 Flush 주기 (밀리초, -1이면 즉시 Flush, 0이면 기본 동작)
+
+	// Backend 헬스체크 (readiness)
+	BackendHealthPath   string
+	BackendHealthStatus []int // 정상 상태 코드 (비어 있으면 2xx)
+	BackendHealthCache  int   // 헬스체크 결과 재사용 기간 (초 단위, 0이면 매번 확인)
+
+	// CACHE_VERSION=backend일 때 버전 조회 엔드포인트
+	BackendVersionPath  string
+	CacheVersionRefresh int // 버전 재조회 주기 (초 단위, 0이면 시작 시 한 번)
+
+	// 카나리 테스트용 Backend 허용 목록 (X-Backend-Target 헤더, 관리자 토큰 필요)
+	BackendTargets []string
+
+	// 쿼리 분류 라우팅 규칙 파일 (JSON, 비어 있으면 비활성화)
+	QueryRoutesFile string
+
+	// 채팅 쿼리 개인정보 마스킹 (기본 규칙 이름 목록, 정규식 규칙 파일, Backend 전달 방식 redacted | original)
+	QueryRedact        []string
+	QueryRedactFile    string
+	QueryRedactForward string
+
+	// "/"에서 제공할 정적 페이지 ("default"면 내장 안내 페이지, 파일 경로, 비어 있으면 404)
+	RootPage string
+
+	// 브라우저(Accept: text/html) 에러 응답용 HTML 템플릿 파일 (비어 있으면 내장 템플릿)
+	ErrorPageTemplate string
+
+	// 관리자 토큰 (X-Admin-Token 헤더)
+	AdminToken string
+
+	// Backend 인증 (Gateway만 보유하는 내부 API 키)
+	BackendAPIKey       string
+	BackendAPIKeyHeader string // Authorization이면 "Bearer <key>" 형식으로 주입
+
+	// Backend mTLS (PEM 파일 경로, 비어 있으면 기본 TLS)
+	BackendTLSClientCert string
+	BackendTLSClientKey  string
+	BackendTLSCA         string // Backend 서버 인증서를 검증할 CA (비어 있으면 시스템 CA)
+
+	// Backend 요청/응답 헤더 정리
+	StripRequestHeaders    []string // Backend로 전달하기 전에 제거할 요청 헤더
+	ForwardHeaderAllowlist []string // Backend로 전달할 요청 헤더 (비어 있으면 전체 전달)
+	ClientIPHeader         string   // 클라이언트 IP 하나를 담아 Backend로 전달할 헤더 (비어 있으면 X-Forwarded-For만)
+	StripResponseHeaders   []string // 클라이언트로 전달하기 전에 제거할 응답 헤더
+	QueryParamAllowlist    []string // 일반 프록시로 전달할 쿼리 파라미터 (비어 있으면 전체 전달)
+	RewriteLocation        bool     // 리다이렉트 Location을 Gateway 호스트로 재작성
+	FollowRedirects        bool     // Backend 리다이렉트를 서버 측에서 따라감 (같은 호스트로만)
 
 	// Redis 설정
-	RedisAddr     string
-	RedisPassword string
+	RedisAddr           string
+	RedisPassword       string
+	RedisMaxRetries     int // 연결 끊김으로 실패한 명령 재시도 횟수 (0이면 재시도 안 함)
+	RedisRetryBackoffMs int // 재시도 대기 시간 시작값 (밀리초, 재시도마다 증가)
+	RedisHealthInterval int // 백그라운드 연결 확인 주기 (초, 0이면 요청마다 PING)
 
 	// Rate Limiter 설정
 	RateLimit float64 // 초당 요청 수
 	RateBurst int     // 버스트 허용량
 
+	// 인증된 클라이언트(API 키) Rate Limiter 설정
+	RateLimitAuth float64
+	RateBurstAuth int
+
+	// IP별 버스트 재정의 ("IP=버스트[:초당요청수]" 목록)
+	IPBurstOverrides []string
+
+	// 추적할 클라이언트 Limiter 수 상한 (LRU 제거, 0이면 무제한)
+	RateLimitMaxClients int
+
+	// 서버 시작 직후 버스트 완화 (0이면 비활성화)
+	RateLimitWarmup           int     // 초 단위
+	RateLimitWarmupMultiplier float64 // 시작 시점 버스트 배수
+
+	// 한도 접근 경고 (남은 토큰 비율 기준, 0이면 비활성화)
+	RateLimitWarnFraction float64
+	RateLimitWarnInterval int // 같은 클라이언트 경고 간격 (초 단위)
+
+	// 모든 응답에 X-RateLimit-* 헤더 추가
+	RateLimitHeaders bool
+
+	// 한도 초과 시 캐시 히트가 있으면 429 대신 캐시로 응답
+	RateLimitCacheFallback bool
+
+	// 대기 모드: 토큰이 없으면 최대 RateLimitMaxWait까지 기다린 뒤 처리 (false면 즉시 429)
+	RateLimitWait    bool
+	RateLimitMaxWait int // 밀리초 단위
+
+	// 적응형 Rate Limiting: Backend 에러율/지연 시간이 기준을 넘으면 초당 요청 수를 비례해서 축소
+	AdaptiveRateLimit        bool
+	AdaptiveErrorThreshold   float64 // 0~1
+	AdaptiveLatencyThreshold int     // 밀리초 단위 (0이면 지연 시간은 무시)
+	AdaptiveMinFactor        float64 // 한도 축소 하한 (설정 한도 대비 비율)
+	AdaptiveWindow           int     // 초 단위, 이 기간 응답이 없으면 원래 한도로 복귀
+
+	// Backend가 불안정한 동안 캐시 저장 건너뛰기 (응답은 그대로 전달)
+	CacheHealthGate           bool
+	CacheHealthErrorThreshold float64 // 0~1, 최근 에러율이 이 값을 넘으면 저장하지 않음
+
+	// 클라이언트 API 키 인증
+	APIKeys      []string // 유효한 API 키 목록 (비어 있으면 모두 익명)
+	APIKeyHeader string
+
+	// 클라이언트 IP 판별 및 IP 필터 (CIDR 또는 단일 IP, 쉼표 구분)
+	TrustedProxies  []string // X-Forwarded-For를 신뢰할 프록시
+	IPAllowlist     []string
+	IPDenylist      []string
+	IPFilterDefault string // 목록에 없는 IP 처리: allow | deny (비어 있으면 허용 목록이 있을 때만 deny)
+
 	// 캐시 설정
-	CacheEnabled bool
-	CacheTTL     int // 초 단위
+	CacheBackend    string // redis | memory (로컬 개발/테스트용 인메모리 LRU)
+	CacheVersion    string // 캐시 키 버전 ("backend"면 BACKEND_VERSION_PATH에서 조회, 비어 있으면 버전 없음)
+	CacheSerializer string // 채팅 캐시 항목 저장 형식: json | binary (Redis 전용, 조회는 형식 자동 판별)
+
+	// /api/cache/stats 항목 크기 집계 (Redis MEMORY USAGE): off | sample | full
+	CacheSizeStats  string
+	CacheSizeSample int // sample 방식의 표본 키 수
+	CacheShards     int // 채팅 캐시 키를 나눌 논리 버킷 수 (1이면 나누지 않음, Redis 전용)
+
+	// write-behind 캐시 저장 (Redis 전용): 큐에 모아 백그라운드에서 파이프라인으로 기록
+	CacheWriteBehind    bool
+	CacheWriteQueue     int    // 큐 용량
+	CacheWriteBatch     int    // 파이프라인 한 번에 기록할 최대 수
+	CacheWriteFlushMs   int    // 배치가 덜 찼어도 기록하는 주기 (밀리초)
+	CacheWriteQueueFull string // 큐가 찼을 때: drop | block
+	CacheEnabled        bool
+	CacheTTL            int    // 초 단위
+	CacheRequired       bool   // true면 Redis 연결이 readiness 조건에 포함
+	CacheDebug          bool   // X-Cache-Key 헤더 노출 여부
+	ClientCacheKeys     bool   // 요청의 X-Cache-Key 헤더로 클라이언트가 채팅 캐시 키를 지정할 수 있는지
+	CacheTenant         string // 테넌트별 캐시 키 공간: 비어 있으면 사용 안 함 | header | api_key
+	CacheTenantHeader   string // CACHE_TENANT=header일 때 테넌트 식별자를 담은 요청 헤더
+	CacheHeaders        bool   // 채팅 응답에 X-Cache-Age / X-Cache-TTL 헤더 추가
+	CacheableStatus     []int  // 동기 채팅 응답을 캐시할 Backend 상태 코드
+
+	// Backend 응답의 Cache-Control 준수 (no-store면 저장 안 함, max-age를 TTL로 사용)
+	BackendCacheControl bool
+	BackendCacheMaxTTL  int // max-age TTL 상한 (초 단위, 0이면 상한 없음)
+
+	// 인스턴스 간 cache stampede 방지 잠금 (CacheLockTTL이 0이면 비활성화, Redis 전용)
+	CacheLockTTL  int // 초 단위
+	CacheLockWait int // 잠금 대기 최대 시간 (밀리초 단위)
+	StaleGrace    int // 만료 후 Backend 장애 fallback용으로 보관하는 기간 (초 단위)
+
+	// LRU 제거 (CacheMaxEntries가 0이면 비활성화, Redis 자체 eviction 정책에만 의존)
+	CacheMaxEntries       int
+	CacheEvictionInterval int // 초 단위
+
+	// 일반 응답 캐시 대상 경로 접두사 (메서드+경로+쿼리+바디 기반 키)
+	CacheablePaths []string
+
+	// 캐시를 거치지 않을 경로 접두사 (CACHE_ENABLED와 무관하게 조회/저장 모두 건너뜀)
+	CacheDisabledPaths []string
+
+	// Backend 장애 시 응답 메시지
+	FallbackMessage string
+
+	// Backend가 빈 답변을 반환했을 때 대신 응답할 메시지 (비어 있으면 그대로 전달)
+	EmptyResponseFallback string
+	CacheEmptyResponses   bool // true면 fallback 메시지로 대체된 답변도 캐시
+
+	// 네거티브 캐시: 404 또는 빈 답변을 짧게 캐시 (초 단위, 0이면 비활성화)
+	NegativeCacheTTL int
+
+	// 캐시 신선도 검사: 캐시 히트 일부를 백그라운드에서 Backend에 다시 질의해 달라진 답변 감지/갱신
+	CacheRefreshProbability   float64 // 검사할 캐시 히트 비율 (0~1, 0이면 비활성화)
+	CacheRefreshMinSimilarity float64 // 이보다 유사도가 낮으면 달라진 것으로 보고 캐시 갱신 (단어 집합 Jaccard, 0~1)
 
 	// 시맨틱 캐시 설정
 	SimilarityThreshold float64 // 유사도 임계값 (0.0 ~ 1.0)
+	EmbeddingProvider   string  // 임베딩 구현: "" (비활성화) | http | fake
+	EmbeddingURL        string  // OpenAI 호환 임베딩 엔드포인트
+	EmbeddingModel      string
+	EmbeddingAPIKey     string
+	EmbeddingTimeout    int  // 초 단위
+	SemanticVectorIndex bool // RediSearch 벡터 인덱스 사용 (없으면 전수 비교로 대체)
+
+	// Backend 동시성 제어
+	BackendMaxConcurrency int // Backend 동시 요청 수 상한 (0이면 무제한)
+	BackendQueueTimeout   int // 슬롯 대기 최대 시간 (초 단위)
+	BackendTimeout        int // 동기 프록시 Backend 요청 타임아웃 (초 단위, 0이면 제한 없음)
+	RequestTimeoutMax     int // X-Request-Timeout 헤더로 지정할 수 있는 최대값 (초 단위, 0이면 헤더 무시)
+
+	// grpc-web 브릿지 설정 (GRPCPathPrefix가 비어 있으면 비활성화)
+	GRPCPathPrefix string
+	GRPCBackendURL string // 비어 있으면 BackendURL 사용
+
+	// CORS 설정 (기본 정책 + 경로별 규칙)
+	CORSAllowedOrigins  []string
+	CORSAllowedMethods  []string
+	CORSAllowedHeaders  []string
+	CORSPathRules       string // "접두사|출처들|메서드들|헤더들;..." 형식
+	CORSStrictPreflight bool   // true면 CORS 허용 라우트의 유효한 preflight에만 응답
+
+	// 접근 로그 설정
+	AccessLogFile       string   // 비어 있으면 표준 로그 출력 사용
+	AccessLogMaxSize    int      // 로테이션 기준 크기 (MB)
+	AccessLogMaxBackups int      // 보관할 이전 로그 파일 개수
+	LogExcludePaths     []string // 접근 로그에서 제외할 경로 (정확 일치, *로 끝나면 접두사)
+	LogFormat           string   // text | combined (Apache/NGINX Combined Log Format)
+	LogSampleRate       float64  // 헤더/바디를 포함한 상세 로그를 남길 요청 비율 (0~1, 0이면 사용 안 함)
+	LogSampleMaxBody    int      // 상세 로그에 기록할 요청/응답 바디 최대 크기 (바이트)
+
+	// 요청 처리 시간 히스토그램 (GET /metrics, Prometheus 형식)
+	MetricsEnabled bool
+	LatencyBuckets []string // 버킷 경계 (초, 비어 있으면 1ms~30s 기본값)
+
+	// 채팅 쿼리 추출 설정
+	QueryJSONField string // 동기 채팅 요청 JSON의 쿼리 필드 이름
+	QueryParamName string // 스트리밍 채팅 요청의 쿼리 파라미터 이름
+
+	// 동기 채팅 응답 변환 (Backend 응답 스키마가 다를 때)
+	ResponseAnswerPath  string // Backend 응답 JSON에서 답변 위치 (점 구분 경로)
+	ResponseAnswerField string // 클라이언트 응답 JSON의 답변 필드 이름
+	ResponseEnvelope    bool   // true면 미스 응답도 히트와 같은 형태로 재구성
+	InjectMetadata      bool   // true면 동기 채팅 JSON 응답에 gateway 메타데이터(버전, 캐시 상태, 처리 시간) 추가
+
+	// 배치 채팅 요청당 최대 질문 수
+	BatchMaxQueries int
+
+	// 채팅 요청 바디 검증: true면 알 수 없는 최상위 필드도 422로 거부
+	ChatValidationStrict bool
+
+	// Content-Encoding: gzip 요청 바디의 압축 해제 후 최대 크기 (MB, zip bomb 방지)
+	GzipMaxBodySize int
+
+	// SSE 설정
+	SSEGzipEnabled        bool     // Accept-Encoding: gzip 클라이언트에 SSE 압축 적용
+	SSEDoneMarkers        []string // 스트림 완료 표시 (data 값 또는 "event:<이름>" 형식)
+	SSEWrapTokens         bool     // 토큰 data를 {"token": "..."} JSON으로 감싸서 전달
+	SSEStoreChunks        bool     // 스트림 캐시에 원본 토큰 청크 경계도 저장 (재생 시 그대로 전송)
+	SSEMaxEvents          int      // 스트림 하나에서 전달할 최대 이벤트 수 (초과 시 event:truncated 후 종료, 0이면 무제한)
+	SSEBufferSize         int      // Backend에서 읽어 클라이언트 전송을 기다리는 최대 줄 수 (가득 차면 Backend 읽기 중단, 줄당 최대 64 KiB)
+	SSEResultEvent        bool     // 스트림 완료 시 전체 텍스트와 메타데이터를 담은 event:result 전달
+	SSEResultBackendEvent string   // event:result에 합칠 Backend 결과 이벤트 이름 (빈 값이면 Gateway가 모은 텍스트만)
+	SyncViaStream         bool     // 동기 채팅을 Backend SSE 스트림으로 처리하고 토큰을 모아 JSON으로 응답
+	StreamMaxDuration     int      // 스트리밍 최대 시간 (초 단위, 0이면 무제한)
+
+	// 스트림 중단 전파: 클라이언트가 완료 전에 끊으면 Backend 중단 엔드포인트 호출
+	SessionIDHeader     string // 스트림 세션 ID를 담은 요청 헤더 (Backend 스트림 요청에도 전달)
+	BackendAbortPath    string // 세션 ID로 POST할 Backend 중단 경로 (비어 있으면 사용 안 함)
+	BackendAbortTimeout int    // 중단 요청 제한 시간 (초)
 }
 
 // Load는 환경 변수에서 설정을 로드
@@ -50,15 +295,161 @@ func Load() *Config {
 	}
 
 	return &Config{
-		Port:                getEnv("GATEWAY_PORT", "8080"),
-		BackendURL:          getEnv("BACKEND_URL", "http://localhost:8081"),
-		RedisAddr:           getEnv("REDIS_HOST", "localhost") + ":" + getEnv("REDIS_PORT", "6379"),
-		RedisPassword:       getEnv("REDIS_PASSWORD", ""),
-		RateLimit:           getEnvFloat("RATE_LIMIT", 10.0), // 초당 요청 수
-		RateBurst:           getEnvInt("RATE_BURST", 20), // 버스트 허용량
-		CacheEnabled:        getEnvBool("CACHE_ENABLED", true),
-		CacheTTL:            getEnvInt("CACHE_TTL", 3600), // 캐시 유지 시간 (초)
-		SimilarityThreshold: getEnvFloat("SIMILARITY_THRESHOLD", 0.95), // 유사도 임계값 (0.0 ~ 1.0)
+		Port:                      getEnv("GATEWAY_PORT", "8080"),
+		MaxConnections:            getEnvInt("MAX_CONNECTIONS", 0),
+		ServerReadHeaderTimeout:   getEnvInt("SERVER_READ_HEADER_TIMEOUT", 10),
+		ServerReadTimeout:         getEnvInt("SERVER_READ_TIMEOUT", 60),
+		ServerWriteTimeout:        getEnvInt("SERVER_WRITE_TIMEOUT", 0),
+		ServerIdleTimeout:         getEnvInt("SERVER_IDLE_TIMEOUT", 120),
+		ShutdownTimeout:           getEnvInt("SHUTDOWN_TIMEOUT", 30),
+		GlobalTimeout:             getEnvInt("GLOBAL_TIMEOUT", 0),
+		GlobalTimeoutBypass:       getEnvList("GLOBAL_TIMEOUT_BYPASS", "/api/chat/stream"),
+		BackendURL:                getEnv("BACKEND_URL", "http://localhost:8081"),
+		BackendURLs:               getEnvList("BACKEND_URLS", ""),
+		BackendDownCooldown:       getEnvInt("BACKEND_DOWN_COOLDOWN", 10),
+		BackendDrainTimeout:       getEnvInt("BACKEND_DRAIN_TIMEOUT", 60),
+		ProxyFlushInterval:        getEnvInt("PROXY_FLUSH_INTERVAL", 0), // 리버스 프록시 Flush 주기 (밀리초)
+		BackendTargets:            getEnvList("BACKEND_TARGETS", ""),
+		QueryRoutesFile:           getEnv("QUERY_ROUTES_FILE", ""),
+		QueryRedact:               getEnvList("QUERY_REDACT", ""),
+		QueryRedactFile:           getEnv("QUERY_REDACT_FILE", ""),
+		QueryRedactForward:        getEnv("QUERY_REDACT_FORWARD", "redacted"),
+		RootPage:                  getEnv("ROOT_PAGE", ""),
+		ErrorPageTemplate:         getEnv("ERROR_PAGE_TEMPLATE", ""),
+		AdminToken:                getEnv("ADMIN_TOKEN", ""),
+		BackendAPIKey:             getEnv("BACKEND_API_KEY", ""),
+		BackendAPIKeyHeader:       getEnv("BACKEND_API_KEY_HEADER", "X-API-Key"),
+		BackendTLSClientCert:      getEnv("BACKEND_TLS_CLIENT_CERT", ""),
+		BackendTLSClientKey:       getEnv("BACKEND_TLS_CLIENT_KEY", ""),
+		BackendTLSCA:              getEnv("BACKEND_TLS_CA", ""),
+		BackendHealthPath:         getEnv("BACKEND_HEALTH_PATH", "/api/health"),
+		BackendVersionPath:        getEnv("BACKEND_VERSION_PATH", "/version"),
+		CacheVersionRefresh:       getEnvInt("CACHE_VERSION_REFRESH", 60),
+		BackendHealthStatus:       getEnvIntList("BACKEND_HEALTH_STATUS", ""),
+		BackendHealthCache:        getEnvInt("BACKEND_HEALTH_CACHE", 2),
+		StripRequestHeaders:       getEnvList("STRIP_REQUEST_HEADERS", ""),
+		ForwardHeaderAllowlist:    getEnvList("FORWARD_HEADER_ALLOWLIST", ""),
+		ClientIPHeader:            getEnv("CLIENT_IP_HEADER", "X-Real-IP"),
+		StripResponseHeaders:      getEnvList("STRIP_RESPONSE_HEADERS", ""),
+		QueryParamAllowlist:       getEnvList("QUERY_PARAM_ALLOWLIST", ""),
+		RewriteLocation:           getEnvBool("REWRITE_LOCATION", false),
+		FollowRedirects:           getEnvBool("FOLLOW_REDIRECTS", false),
+		RedisAddr:                 getEnv("REDIS_HOST", "localhost") + ":" + getEnv("REDIS_PORT", "6379"),
+		RedisMaxRetries:           getEnvInt("REDIS_MAX_RETRIES", 3),
+		RedisRetryBackoffMs:       getEnvInt("REDIS_RETRY_BACKOFF_MS", 8),
+		RedisHealthInterval:       getEnvInt("REDIS_HEALTH_INTERVAL", 1),
+		RedisPassword:             getEnv("REDIS_PASSWORD", ""),
+		RateLimit:                 getEnvFloat("RATE_LIMIT", 10.0),      // 초당 요청 수
+		RateBurst:                 getEnvInt("RATE_BURST", 20),          // 버스트 허용량
+		RateLimitAuth:             getEnvFloat("RATE_LIMIT_AUTH", 50.0), // 인증된 클라이언트 초당 요청 수
+		RateBurstAuth:             getEnvInt("RATE_BURST_AUTH", 100),
+		IPBurstOverrides:          getEnvList("IP_BURST_OVERRIDES", ""),
+		RateLimitMaxClients:       getEnvInt("RATE_LIMIT_MAX_CLIENTS", 10000),
+		RateLimitWarmup:           getEnvInt("RATE_LIMIT_WARMUP_SECONDS", 0),
+		RateLimitWarmupMultiplier: getEnvFloat("RATE_LIMIT_WARMUP_MULTIPLIER", 5.0),
+		RateLimitWarnFraction:     getEnvFloat("RATE_LIMIT_WARN_FRACTION", 0),
+		RateLimitWarnInterval:     getEnvInt("RATE_LIMIT_WARN_INTERVAL", 60),
+		RateLimitHeaders:          getEnvBool("RATE_LIMIT_HEADERS", false),
+		RateLimitCacheFallback:    getEnvBool("RATE_LIMIT_CACHE_FALLBACK", false),
+		RateLimitWait:             getEnvBool("RATE_LIMIT_WAIT", false),
+		RateLimitMaxWait:          getEnvInt("RATE_LIMIT_MAX_WAIT", 500),
+		AdaptiveRateLimit:         getEnvBool("ADAPTIVE_RATE_LIMIT", false),
+		AdaptiveErrorThreshold:    getEnvFloat("ADAPTIVE_ERROR_THRESHOLD", 0.1),
+		AdaptiveLatencyThreshold:  getEnvInt("ADAPTIVE_LATENCY_THRESHOLD", 5000),
+		AdaptiveMinFactor:         getEnvFloat("ADAPTIVE_MIN_FACTOR", 0.2),
+		AdaptiveWindow:            getEnvInt("ADAPTIVE_WINDOW", 30),
+		CacheHealthGate:           getEnvBool("CACHE_HEALTH_GATE", false),
+		CacheHealthErrorThreshold: getEnvFloat("CACHE_HEALTH_ERROR_THRESHOLD", 0.1),
+		APIKeys:                   getEnvList("API_KEYS", ""),
+		APIKeyHeader:              getEnv("API_KEY_HEADER", "X-API-Key"),
+		TrustedProxies:            getEnvList("TRUSTED_PROXIES", ""),
+		IPAllowlist:               getEnvList("IP_ALLOWLIST", ""),
+		IPDenylist:                getEnvList("IP_DENYLIST", ""),
+		IPFilterDefault:           getEnv("IP_FILTER_DEFAULT", ""),
+		CacheBackend:              getEnv("CACHE_BACKEND", "redis"),
+		CacheVersion:              getEnv("CACHE_VERSION", ""),
+		CacheSerializer:           getEnv("CACHE_SERIALIZER", "json"),
+		CacheSizeStats:            getEnv("CACHE_SIZE_STATS", "off"),
+		CacheSizeSample:           getEnvInt("CACHE_SIZE_SAMPLE", 100),
+		CacheShards:               getEnvInt("CACHE_SHARDS", 1),
+		CacheWriteBehind:          getEnvBool("CACHE_WRITE_BEHIND", false),
+		CacheWriteQueue:           getEnvInt("CACHE_WRITE_QUEUE", 1000),
+		CacheWriteBatch:           getEnvInt("CACHE_WRITE_BATCH", 100),
+		CacheWriteFlushMs:         getEnvInt("CACHE_WRITE_FLUSH_MS", 10),
+		CacheWriteQueueFull:       getEnv("CACHE_WRITE_QUEUE_FULL", "drop"),
+		CacheEnabled:              getEnvBool("CACHE_ENABLED", true),
+		CacheTTL:                  getEnvInt("CACHE_TTL", 3600), // 캐시 유지 시간 (초)
+		CacheRequired:             getEnvBool("CACHE_REQUIRED", false),
+		CacheDebug:                getEnvBool("CACHE_DEBUG", false),
+		ClientCacheKeys:           getEnvBool("CLIENT_CACHE_KEYS", false),
+		CacheTenant:               getEnv("CACHE_TENANT", ""),
+		CacheTenantHeader:         getEnv("CACHE_TENANT_HEADER", "X-Tenant-ID"),
+		CacheHeaders:              getEnvBool("CACHE_HEADERS", false),
+		CacheableStatus:           getEnvIntList("CACHEABLE_STATUS", "200"),
+		BackendCacheControl:       getEnvBool("BACKEND_CACHE_CONTROL", true),
+		BackendCacheMaxTTL:        getEnvInt("BACKEND_CACHE_MAX_TTL", 86400),
+		CacheLockTTL:              getEnvInt("CACHE_LOCK_TTL", 0),
+		CacheLockWait:             getEnvInt("CACHE_LOCK_WAIT", 3000),
+		StaleGrace:                getEnvInt("STALE_GRACE_PERIOD", 0), // 만료 후 fallback 보관 기간 (초)
+		CacheMaxEntries:           getEnvInt("CACHE_MAX_ENTRIES", 0),
+		CacheEvictionInterval:     getEnvInt("CACHE_EVICTION_INTERVAL", 60), // LRU 제거 주기 (초)
+		CacheablePaths:            getEnvList("CACHEABLE_PATHS", ""),
+		CacheDisabledPaths:        getEnvList("CACHE_DISABLED_PATHS", ""),
+		FallbackMessage:           getEnv("FALLBACK_MESSAGE", "백엔드 서버에 연결할 수 없습니다."),
+		EmptyResponseFallback:     getEnv("EMPTY_RESPONSE_FALLBACK", ""),
+		CacheEmptyResponses:       getEnvBool("CACHE_EMPTY_RESPONSES", false),
+		NegativeCacheTTL:          getEnvInt("NEGATIVE_CACHE_TTL", 0),
+		CacheRefreshProbability:   getEnvFloat("CACHE_REFRESH_PROBABILITY", 0),
+		CacheRefreshMinSimilarity: getEnvFloat("CACHE_REFRESH_MIN_SIMILARITY", 0.5),
+		SimilarityThreshold:       getEnvFloat("SIMILARITY_THRESHOLD", 0.95), // 유사도 임계값 (0.0 ~ 1.0)
+		EmbeddingProvider:         getEnv("EMBEDDING_PROVIDER", ""),
+		EmbeddingURL:              getEnv("EMBEDDING_URL", "https://api.openai.com/v1/embeddings"),
+		EmbeddingModel:            getEnv("EMBEDDING_MODEL", "text-embedding-3-small"),
+		EmbeddingAPIKey:           getEnv("EMBEDDING_API_KEY", ""),
+		EmbeddingTimeout:          getEnvInt("EMBEDDING_TIMEOUT", 5), // 임베딩 요청 타임아웃 (초)
+		SemanticVectorIndex:       getEnvBool("SEMANTIC_VECTOR_INDEX", false),
+		BackendMaxConcurrency:     getEnvInt("BACKEND_MAX_CONCURRENCY", 0), // Backend 동시 요청 수 상한
+		BackendQueueTimeout:       getEnvInt("BACKEND_QUEUE_TIMEOUT", 5),   // 슬롯 대기 최대 시간 (초)
+		BackendTimeout:            getEnvInt("BACKEND_TIMEOUT", 0),
+		RequestTimeoutMax:         getEnvInt("REQUEST_TIMEOUT_MAX", 0),
+		GRPCPathPrefix:            getEnv("GRPC_PATH_PREFIX", ""),
+		GRPCBackendURL:            getEnv("GRPC_BACKEND_URL", ""),
+		CORSAllowedOrigins:        getEnvList("CORS_ALLOWED_ORIGINS", "*"),
+		CORSAllowedMethods:        getEnvList("CORS_ALLOWED_METHODS", "GET,POST,PUT,DELETE,OPTIONS"),
+		CORSAllowedHeaders:        getEnvList("CORS_ALLOWED_HEADERS", "Content-Type,Authorization"),
+		CORSPathRules:             getEnv("CORS_PATH_RULES", ""),
+		CORSStrictPreflight:       getEnvBool("CORS_STRICT_PREFLIGHT", true),
+		AccessLogFile:             getEnv("ACCESS_LOG_FILE", ""),
+		AccessLogMaxSize:          getEnvInt("ACCESS_LOG_MAX_SIZE", 100), // 로테이션 기준 크기 (MB)
+		AccessLogMaxBackups:       getEnvInt("ACCESS_LOG_MAX_BACKUPS", 5),
+		LogExcludePaths:           getEnvList("LOG_EXCLUDE_PATHS", ""),
+		LogFormat:                 getEnv("LOG_FORMAT", "text"),
+		LogSampleRate:             getEnvFloat("LOG_SAMPLE_RATE", 0),
+		LogSampleMaxBody:          getEnvInt("LOG_SAMPLE_MAX_BODY", 4096),
+		MetricsEnabled:            getEnvBool("METRICS_ENABLED", false),
+		LatencyBuckets:            getEnvList("LATENCY_BUCKETS", ""),
+		QueryJSONField:            getEnv("QUERY_JSON_FIELD", "query"),
+		QueryParamName:            getEnv("QUERY_PARAM_NAME", "q"),
+		ResponseAnswerPath:        getEnv("RESPONSE_ANSWER_PATH", "response"),
+		ResponseAnswerField:       getEnv("RESPONSE_ANSWER_FIELD", "response"),
+		ResponseEnvelope:          getEnvBool("RESPONSE_ENVELOPE", false),
+		InjectMetadata:            getEnvBool("INJECT_METADATA", false),
+		BatchMaxQueries:           getEnvInt("BATCH_MAX_QUERIES", 10),
+		ChatValidationStrict:      getEnvBool("CHAT_VALIDATION_STRICT", false),
+		GzipMaxBodySize:           getEnvInt("GZIP_MAX_BODY_SIZE", 10),   // 압축 해제 후 최대 크기 (MB)
+		SSEGzipEnabled:            getEnvBool("SSE_GZIP_ENABLED", false), // 이벤트마다 Flush하므로 압축률은 낮음
+		SSEDoneMarkers:            getEnvList("SSE_DONE_MARKERS", "[DONE],event:done"),
+		SSEWrapTokens:             getEnvBool("SSE_WRAP_TOKENS", false),
+		SSEStoreChunks:            getEnvBool("SSE_STORE_CHUNKS", false),
+		SSEMaxEvents:              getEnvInt("SSE_MAX_EVENTS", 0),
+		SSEBufferSize:             getEnvInt("SSE_BUFFER_SIZE", 16),
+		SSEResultEvent:            getEnvBool("SSE_RESULT_EVENT", false),
+		SSEResultBackendEvent:     getEnv("SSE_RESULT_BACKEND_EVENT", "result"),
+		SyncViaStream:             getEnvBool("SYNC_VIA_STREAM", false),
+		StreamMaxDuration:         getEnvInt("STREAM_MAX_DURATION", 300), // 스트리밍 최대 시간 (초)
+		SessionIDHeader:           getEnv("SESSION_ID_HEADER", "X-Session-ID"),
+		BackendAbortPath:          getEnv("BACKEND_ABORT_PATH", ""),
+		BackendAbortTimeout:       getEnvInt("BACKEND_ABORT_TIMEOUT", 5),
 	}
 }
 
@@ -69,6 +460,28 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getEnvList는 쉼표로 구분된 환경 변수를 목록으로 반환 (빈 항목 제외)
+func getEnvList(key, defaultValue string) []string {
+	var list []string
+	for _, item := range strings.Split(getEnv(key, defaultValue), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// getEnvIntList는 쉼표로 구분된 정수 목록 환경 변수 반환 (숫자가 아닌 항목 제외)
+func getEnvIntList(key, defaultValue string) []int {
+	var list []int
+	for _, item := range getEnvList(key, defaultValue) {
+		if i, err := strconv.Atoi(item); err == nil {
+			list = append(list, i)
+		}
+	}
+	return list
+}
+
 func getEnvInt(key string, defaultValue int) int {
 	if value := os.Getenv(key); value != "" {
 		if i, err := strconv.Atoi(value); err == nil {
@@ -93,4 +506,3 @@ func getEnvBool(key string, defaultValue bool) bool {
 	}
 	return defaultValue
 }
-
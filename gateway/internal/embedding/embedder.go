@@ -0,0 +1,131 @@
+package embedding
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Embedder는 텍스트를 벡터로 변환하는 임베딩 모델
+type Embedder interface {
+	Embed(ctx context.Context, text string) ([]float32, error)
+}
+
+// HTTPEmbedder는 OpenAI 호환 임베딩 엔드포인트(POST /v1/embeddings)를 호출하는 Embedder
+type HTTPEmbedder struct {
+	url    string
+	model  string
+	apiKey string
+	client *http.Client
+}
+
+// NewHTTPEmbedder는 새로운 HTTPEmbedder 생성
+func NewHTTPEmbedder(url, model, apiKey string, timeout time.Duration) *HTTPEmbedder {
+	return &HTTPEmbedder{
+		url:    url,
+		model:  model,
+		apiKey: apiKey,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Embed는 임베딩 엔드포인트를 호출해 벡터 반환
+func (e *HTTPEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	payload, err := json.Marshal(map[string]any{
+		"model": e.model,
+		"input": text,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal embedding request failed: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("create embedding request failed: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+e.apiKey)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("embedding request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("embedding request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var result struct {
+		Data []struct {
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode embedding response failed: %w", err)
+	}
+	if len(result.Data) == 0 || len(result.Data[0].Embedding) == 0 {
+		return nil, fmt.Errorf("embedding response has no data")
+	}
+
+	return result.Data[0].Embedding, nil
+}
+
+// FakeEmbedder는 외부 호출 없이 텍스트 해시로 결정적인 벡터를 만드는 Embedder (테스트/로컬 개발용)
+// 정규화된 텍스트가 같으면 같은 벡터를 반환하지만 의미적 유사도는 반영하지 않는다.
+type FakeEmbedder struct {
+	Dimensions int
+}
+
+// Embed는 텍스트 해시 기반의 단위 벡터 반환
+func (e FakeEmbedder) Embed(_ context.Context, text string) ([]float32, error) {
+	dims := e.Dimensions
+	if dims <= 0 {
+		dims = 8
+	}
+
+	normalized := strings.Join(strings.Fields(strings.ToLower(text)), " ")
+	vector := make([]float32, dims)
+	var norm float64
+	for i := range vector {
+		sum := sha256.Sum256([]byte(fmt.Sprintf("%d:%s", i, normalized)))
+		v := float64(int32(binary.BigEndian.Uint32(sum[:4]))) / math.MaxInt32
+		vector[i] = float32(v)
+		norm += v * v
+	}
+
+	norm = math.Sqrt(norm)
+	for i := range vector {
+		vector[i] = float32(float64(vector[i]) / norm)
+	}
+	return vector, nil
+}
+
+// CosineSimilarity는 두 벡터의 코사인 유사도 반환 (길이가 다르면 0)
+func CosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
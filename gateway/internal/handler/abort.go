@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// streamSessions는 세션별 진행 중인 SSE 스트림 수 (BACKEND_ABORT_PATH)
+// 같은 세션이 재연결한 스트림이 남아 있으면 이전 스트림이 끊겨도 Backend 생성을 중단하지 않도록 센다.
+type streamSessions struct {
+	mu     sync.Mutex
+	active map[string]int
+}
+
+// track은 세션의 스트림 하나를 등록하고, 끝날 때 호출할 함수 반환
+// 반환한 함수는 그 세션의 마지막 스트림이었으면 true
+func (s *streamSessions) track(id string) func() bool {
+	s.mu.Lock()
+	if s.active == nil {
+		s.active = make(map[string]int)
+	}
+	s.active[id]++
+	s.mu.Unlock()
+
+	return func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.active[id]--
+		if s.active[id] > 0 {
+			return false
+		}
+		delete(s.active, id)
+		return true
+	}
+}
+
+// count는 스트림이 진행 중인 세션 수
+func (s *streamSessions) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.active)
+}
+
+// streamSessionID는 Backend 중단 요청에 사용할 세션 ID (SESSION_ID_HEADER, 중단 기능이 꺼져 있으면 빈 문자열)
+func (h *ProxyHandler) streamSessionID(r *http.Request) string {
+	if h.config.BackendAbortPath == "" || h.config.SessionIDHeader == "" {
+		return ""
+	}
+	return r.Header.Get(h.config.SessionIDHeader)
+}
+
+// abortStream은 클라이언트가 끊은 스트림의 세션 ID로 Backend 중단 엔드포인트 호출 (BACKEND_ABORT_PATH)
+// 요청 컨텍스트는 이미 취소되었으므로 별도 제한 시간으로 백그라운드에서 호출하며, 실패는 로그만 남긴다.
+func (h *ProxyHandler) abortStream(b *backend, sessionID string) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(h.config.BackendAbortTimeout)*time.Second)
+		defer cancel()
+
+		if err := h.postAbort(ctx, b, sessionID); err != nil {
+			log.Printf("⚠️ Backend 스트림 중단 요청 실패 (세션 %s): %v", sessionID, err)
+			return
+		}
+		log.Printf("🛑 Backend 스트림 중단 요청 (세션 %s)", sessionID)
+	}()
+}
+
+// postAbort는 {"session_id": "..."} 바디와 SESSION_ID_HEADER로 중단 요청 전송 (2xx가 아니면 에러)
+func (h *ProxyHandler) postAbort(ctx context.Context, b *backend, sessionID string) error {
+	body, _ := json.Marshal(map[string]string{"session_id": sessionID})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.url.String()+h.config.BackendAbortPath, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(h.config.SessionIDHeader, sessionID)
+	h.injectBackendAuth(req)
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// healthSampleWeight는 Backend 상태 이동 평균(EWMA)에서 새 응답 하나의 가중치
+const healthSampleWeight = 0.1
+
+// backendHealth는 최근 Backend 응답의 에러율/지연 시간 이동 평균으로
+// 적응형 Rate Limiting 계수를 계산 (ADAPTIVE_RATE_LIMIT), 캐시 저장 제한(CACHE_HEALTH_GATE)에도 사용
+type backendHealth struct {
+	mu         sync.Mutex
+	errorRate  float64 // 0~1 (연결 실패, 5xx)
+	latencyMs  float64 // 응답 헤더까지의 지연 시간
+	samples    int64
+	lastSample time.Time
+	lastFactor float64 // 계수 변화 로그용
+
+	errorThreshold   float64       // 이 에러율을 넘으면 한도 축소
+	latencyThreshold time.Duration // 이 지연 시간을 넘으면 한도 축소
+	minFactor        float64       // 계수 하한 (한도를 완전히 막지 않음)
+	window           time.Duration // 이 기간 동안 응답이 없으면 회복된 것으로 간주
+}
+
+// newBackendHealth는 설정값으로 backendHealth 생성
+func newBackendHealth(errorThreshold float64, latencyThreshold time.Duration, minFactor float64, window time.Duration) *backendHealth {
+	return &backendHealth{
+		errorThreshold:   errorThreshold,
+		latencyThreshold: latencyThreshold,
+		minFactor:        math.Min(math.Max(minFactor, 0.01), 1),
+		window:           window,
+		lastFactor:       1,
+	}
+}
+
+// observe는 Backend 응답 하나를 이동 평균에 반영 (nil이면 무시)
+func (bh *backendHealth) observe(latency time.Duration, failed bool) {
+	if bh == nil {
+		return
+	}
+
+	failure := 0.0
+	if failed {
+		failure = 1
+	}
+	ms := float64(latency) / float64(time.Millisecond)
+
+	bh.mu.Lock()
+	// 오래 응답이 없었으면 이전 평균은 버리고 새로 시작
+	if bh.window > 0 && time.Since(bh.lastSample) > bh.window {
+		bh.samples = 0
+	}
+	if bh.samples == 0 {
+		bh.errorRate, bh.latencyMs = failure, ms
+	} else {
+		bh.errorRate += healthSampleWeight * (failure - bh.errorRate)
+		bh.latencyMs += healthSampleWeight * (ms - bh.latencyMs)
+	}
+	bh.samples++
+	bh.lastSample = time.Now()
+	factor := bh.factorLocked()
+	changed := math.Abs(factor-bh.lastFactor) >= 0.1 || (factor == 1) != (bh.lastFactor == 1)
+	if changed {
+		bh.lastFactor = factor
+	}
+	errorRate, latencyMs := bh.errorRate, bh.latencyMs
+	bh.mu.Unlock()
+
+	if changed {
+		log.Printf("🩺 적응형 Rate Limit 계수 %.2f (에러율 %.2f, 지연 %.0fms)", factor, errorRate, latencyMs)
+	}
+}
+
+// factorLocked는 현재 이동 평균으로 계수 계산 (mu 보유 상태에서 호출)
+// 에러율/지연 시간이 기준을 넘은 만큼 비례해서 줄이며 둘 중 작은 값을 사용
+func (bh *backendHealth) factorLocked() float64 {
+	if bh.samples == 0 || (bh.window > 0 && time.Since(bh.lastSample) > bh.window) {
+		return 1
+	}
+
+	factor := 1.0
+	if bh.errorThreshold < 1 && bh.errorRate > bh.errorThreshold {
+		factor = math.Min(factor, 1-(bh.errorRate-bh.errorThreshold)/(1-bh.errorThreshold))
+	}
+	if threshold := float64(bh.latencyThreshold / time.Millisecond); threshold > 0 && bh.latencyMs > threshold {
+		factor = math.Min(factor, threshold/bh.latencyMs)
+	}
+	return math.Max(factor, bh.minFactor)
+}
+
+// factor는 현재 계수 반환 (nil이면 1 = 설정된 한도 그대로)
+func (bh *backendHealth) factor() float64 {
+	if bh == nil {
+		return 1
+	}
+	bh.mu.Lock()
+	defer bh.mu.Unlock()
+	return bh.factorLocked()
+}
+
+// recentErrorRate는 최근 에러율 이동 평균 (nil이거나 window 동안 응답이 없었으면 0)
+func (bh *backendHealth) recentErrorRate() float64 {
+	if bh == nil {
+		return 0
+	}
+	bh.mu.Lock()
+	defer bh.mu.Unlock()
+	if bh.samples == 0 || (bh.window > 0 && time.Since(bh.lastSample) > bh.window) {
+		return 0
+	}
+	return bh.errorRate
+}
+
+// status는 진단 리포트용 현재 상태
+func (bh *backendHealth) status() map[string]any {
+	bh.mu.Lock()
+	defer bh.mu.Unlock()
+	return map[string]any{
+		"error_rate": bh.errorRate,
+		"latency_ms": int64(bh.latencyMs),
+		"samples":    bh.samples,
+		"factor":     bh.factorLocked(),
+	}
+}
+
+// HealthFactor는 적응형 Rate Limiting 계수 반환 (middleware.HealthSource 구현)
+// ADAPTIVE_RATE_LIMIT이 꺼져 있으면 항상 1
+func (h *ProxyHandler) HealthFactor() float64 {
+	return h.health.factor()
+}
+
+// degradedReason은 CACHE_HEALTH_GATE 설정 시 Backend가 불안정해 응답을 캐시하지 않을 이유 반환 (안정하면 빈 문자열)
+// 드레이닝/일시 제외 상태인 Backend가 있거나 최근 에러율이 CACHE_HEALTH_ERROR_THRESHOLD를 넘으면 불안정으로 판단
+// 불안정한 동안의 응답은 그대로 전달하되 잘못된 답변이 캐시에 남지 않도록 저장만 건너뜀
+func (h *ProxyHandler) degradedReason() string {
+	if !h.config.CacheHealthGate {
+		return ""
+	}
+
+	now := time.Now()
+	for _, b := range h.knownBackends() {
+		if state := b.state(now); state != backendActive {
+			return fmt.Sprintf("%s %s", b.url.Host, state)
+		}
+	}
+	if rate := h.health.recentErrorRate(); rate > h.config.CacheHealthErrorThreshold {
+		return fmt.Sprintf("에러율 %.2f", rate)
+	}
+	return ""
+}
+
+// withBackendStart는 프록시 응답 훅에서 지연 시간을 계산하도록 요청 시작 시각을 저장
+func withBackendStart(r *http.Request, start time.Time) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), backendStartContextKey, start))
+}
+
+// observeProxy는 리버스 프록시 응답(또는 에러)을 Backend 상태에 반영
+func (h *ProxyHandler) observeProxy(ctx context.Context, failed bool) {
+	if h.health == nil {
+		return
+	}
+	if start, ok := ctx.Value(backendStartContextKey).(time.Time); ok {
+		h.health.observe(time.Since(start), failed)
+	}
+}
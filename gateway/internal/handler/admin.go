@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devbrain/gateway/internal/cache"
+	"github.com/devbrain/gateway/internal/config"
+	"github.com/devbrain/gateway/internal/middleware"
+)
+
+// diagnosticsTimeout는 진단 항목별 최대 대기 시간
+const diagnosticsTimeout = 2 * time.Second
+
+// redacted는 진단 응답에서 비밀 값을 대체하는 문자열
+const redacted = "[REDACTED]"
+
+// adminTokenHeader는 관리자 토큰을 전달하는 요청 헤더
+const adminTokenHeader = "X-Admin-Token"
+
+// isAdmin은 요청이 관리자 토큰을 가지고 있는지 확인
+// ADMIN_TOKEN이 설정되지 않았으면 항상 false
+func (h *ProxyHandler) isAdmin(r *http.Request) bool {
+	if h.config.AdminToken == "" {
+		return false
+	}
+
+	token := r.Header.Get(adminTokenHeader)
+	return subtle.ConstantTimeCompare([]byte(token), []byte(h.config.AdminToken)) == 1
+}
+
+// requireAdmin은 관리자 토큰이 없으면 401 응답 후 false 반환
+func (h *ProxyHandler) requireAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if h.isAdmin(r) {
+		return true
+	}
+	middleware.WriteError(w, r, http.StatusUnauthorized, "관리자 토큰이 필요합니다.")
+	return false
+}
+
+// checkResult는 진단 항목 하나의 결과
+type checkResult struct {
+	OK        bool   `json:"ok"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// runCheck는 타임아웃을 적용해 check를 실행하고 소요 시간을 측정
+func runCheck(ctx context.Context, check func(ctx context.Context) error) checkResult {
+	ctx, cancel := context.WithTimeout(ctx, diagnosticsTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	result := checkResult{
+		OK:        err == nil,
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// handleDiagnostics는 운영자용 진단 리포트 반환 (관리자 전용)
+// Redis/Backend/캐시 항목 수 확인은 동시에 실행하여 엔드포인트 자체를 빠르게 유지
+func (h *ProxyHandler) handleDiagnostics(w http.ResponseWriter, r *http.Request) {
+	if !h.requireAdmin(w, r) {
+		return
+	}
+
+	ctx := r.Context()
+
+	var (
+		wg         sync.WaitGroup
+		redisCheck checkResult
+		backend    checkResult
+		entries    int
+		entriesErr error
+	)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		backend = runCheck(ctx, h.probeBackend)
+	}()
+
+	// 캐시 연결 확인/항목 수/명령 지표는 지원하는 캐시 구현만 포함
+	diag, _ := h.cache.(cache.Diagnoser)
+	if diag != nil {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			redisCheck = runCheck(ctx, diag.Ping)
+		}()
+		go func() {
+			defer wg.Done()
+			countCtx, cancel := context.WithTimeout(ctx, diagnosticsTimeout)
+			defer cancel()
+			entries, entriesErr = diag.Count(countCtx)
+		}()
+	}
+	wg.Wait()
+
+	cacheReport := map[string]any{}
+	if diag != nil {
+		cacheReport["entries"] = entries
+		cacheReport["operations"] = diag.Metrics()
+	}
+	if entriesErr != nil {
+		cacheReport["error"] = entriesErr.Error()
+	}
+
+	report := map[string]any{
+		"config":         h.redactedConfig(),
+		"redis":          redisCheck,
+		"backend":        backend,
+		"cache":          cacheReport,
+		"active_streams": h.activeStreams.Load(),
+		"stream_stalls":  h.streamStalls.Load(),
+		"draining":       false,
+	}
+	if since, ok := h.drainStarted(); ok {
+		report["draining"] = true
+		report["draining_since"] = since.Format(time.RFC3339)
+	}
+	if h.pool != nil {
+		report["backend_pool"] = h.pool.status()
+	}
+	if h.health != nil {
+		report["backend_health"] = h.health.status()
+	}
+	if h.config.BackendAbortPath != "" {
+		report["stream_sessions"] = h.sessions.count()
+	}
+	if h.latency != nil {
+		report["latency"] = h.latency.Summary()
+	}
+	if h.rateLimiter != nil {
+		report["rate_limiters"] = h.rateLimiter.Len()
+		report["rate_limit_near_warnings"] = h.rateLimiter.NearLimitWarnings()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// redactedConfig는 비밀 값을 가린 설정 사본 반환
+func (h *ProxyHandler) redactedConfig() config.Config {
+	cfg := *h.config
+	for _, secret := range []*string{&cfg.RedisPassword, &cfg.BackendAPIKey, &cfg.AdminToken, &cfg.EmbeddingAPIKey} {
+		if *secret != "" {
+			*secret = redacted
+		}
+	}
+	return cfg
+}
+
+// rateLimitSnapshotSize는 /admin/ratelimit이 반환하는 최근 클라이언트 최대 수
+const rateLimitSnapshotSize = 50
+
+// handleRateLimitState는 Rate Limiter 상태 반환 (관리자 전용)
+// ?ip=가 있으면 해당 IP의 토큰 수와 제한 여부, 없으면 추적 중인 클라이언트 수와 최근 요청한 클라이언트 목록
+func (h *ProxyHandler) handleRateLimitState(w http.ResponseWriter, r *http.Request) {
+	if !h.requireAdmin(w, r) {
+		return
+	}
+
+	if h.rateLimiter == nil {
+		middleware.WriteError(w, r, http.StatusNotFound, "Rate Limiter가 설정되지 않았습니다.")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if ip := r.URL.Query().Get("ip"); ip != "" {
+		state, ok := h.rateLimiter.ClientState("ip:" + ip)
+		if !ok {
+			middleware.WriteError(w, r, http.StatusNotFound, "추적 중인 클라이언트가 아닙니다.")
+			return
+		}
+		json.NewEncoder(w).Encode(state)
+		return
+	}
+
+	snapshot := h.rateLimiter.Snapshot(rateLimitSnapshotSize)
+	for i := range snapshot.Recent {
+		snapshot.Recent[i].Key = redactClientKey(snapshot.Recent[i].Key)
+	}
+	json.NewEncoder(w).Encode(snapshot)
+}
+
+// redactClientKey는 API 키 기반 클라이언트 식별자를 앞 4자만 남기고 가림
+func redactClientKey(key string) string {
+	apiKey, ok := strings.CutPrefix(key, "key:")
+	if !ok {
+		return key
+	}
+	return "key:" + apiKey[:min(4, len(apiKey))] + "..."
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/devbrain/gateway/internal/middleware"
+)
+
+// streamURL은 Backend의 SSE 스트리밍 엔드포인트 URL
+func (h *ProxyHandler) streamURL(b *backend, query string) string {
+	return fmt.Sprintf("%s/api/chat/stream?%s=%s", b.url.String(), url.QueryEscape(h.config.QueryParamName), url.QueryEscape(query))
+}
+
+// fetchViaStream은 SYNC_VIA_STREAM 설정 시 동기 채팅을 Backend SSE 스트림으로 처리 (sendCachedSSE의 반대 방향)
+// 토큰을 모두 모아 {"<RESPONSE_ANSWER_FIELD>": "..."} JSON으로 buf에 기록하고, 완료된 스트림만 캐시에 저장
+// 스트림 요청에는 쿼리만 전달되므로 바디의 다른 파라미터는 Backend로 전달되지 않는다.
+func (h *ProxyHandler) fetchViaStream(buf *bufferedResponse, r *http.Request, query string) {
+	collector, header, ok := h.collectStream(buf, r, query)
+	if !ok {
+		return
+	}
+
+	log.Printf("🧩 스트림 집계 완료: %s", query[:min(30, len(query))])
+	buf.Header().Set("Content-Type", "application/json")
+	buf.Write(h.missBody(query, collector.text.String()))
+	h.cacheStreamResponse(r.Context(), query, header, collector)
+}
+
+// collectStream은 Backend SSE 스트림을 끝까지 읽어 토큰을 모음
+// 완료 표시까지 받은 경우에만 ok이며, 아니면 에러 응답(Backend 에러 응답은 그대로)을 buf에 기록
+func (h *ProxyHandler) collectStream(buf *bufferedResponse, r *http.Request, query string) (*sseCollector, http.Header, bool) {
+	release, err := h.backendLimiter.acquire(r.Context())
+	if err != nil {
+		writeBackendBusy(buf, r, err)
+		return nil, nil, false
+	}
+	defer release()
+
+	ctx, cancel, ok := h.backendContext(buf, r, time.Duration(h.config.StreamMaxDuration)*time.Second)
+	if !ok {
+		return nil, nil, false
+	}
+	defer cancel()
+
+	b := h.selectBackend(r)
+	untrack := b.track()
+	defer untrack()
+	backendReq, err := http.NewRequestWithContext(ctx, http.MethodGet, h.streamURL(b, h.backendQuery(r.Context(), query)), nil)
+	if err != nil {
+		log.Printf("❌ Backend 요청 생성 실패: %v", err)
+		middleware.WriteError(buf, r, http.StatusInternalServerError, "")
+		return nil, nil, false
+	}
+	backendReq.Header.Set("Accept", "text/event-stream")
+	h.rewriteBackendRequest(backendReq, h.clientIP(r))
+
+	start := time.Now()
+	resp, err := h.backendClient.Do(backendReq)
+	switch {
+	case err == nil:
+		h.health.observe(time.Since(start), resp.StatusCode >= http.StatusInternalServerError)
+	case !errors.Is(err, context.Canceled):
+		h.health.observe(time.Since(start), true)
+		h.drain(b, err.Error())
+	}
+	if err != nil {
+		h.handleProxyError(buf, r, err)
+		return nil, nil, false
+	}
+	defer resp.Body.Close()
+	middleware.AddLogField(r.Context(), "backend", b.url.Host)
+
+	// Backend 에러/리다이렉트 응답은 스트림이 아니므로 집계하지 않고 그대로 전달 (REWRITE_LOCATION 적용)
+	if resp.StatusCode != http.StatusOK {
+		for k, values := range resp.Header {
+			buf.Header()[k] = values
+		}
+		if h.config.RewriteLocation {
+			rewriteLocationHeader(buf.Header(), backendReq.URL.Host, r)
+		}
+		buf.WriteHeader(resp.StatusCode)
+		io.Copy(buf, resp.Body)
+		return nil, nil, false
+	}
+
+	collector := newSSECollector(h.doneMarkers)
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		collector.feed(scanner.Text())
+	}
+	if scanner.Err() == nil {
+		collector.flush()
+	}
+
+	// 완료 표시 없이 끊긴 스트림은 부분 응답을 돌려주지 않음 (시간 초과는 504)
+	if ctx.Err() != nil || scanner.Err() != nil || !collector.done {
+		log.Printf("⚠️ 불완전한 스트림 (동기 집계): %s (canceled=%t, done=%t)",
+			query[:min(30, len(query))], ctx.Err() != nil, collector.done)
+		if ctx.Err() == context.DeadlineExceeded {
+			middleware.WriteError(buf, r, http.StatusGatewayTimeout, "stream exceeded maximum duration")
+			return nil, nil, false
+		}
+		h.writeBackendUnavailable(buf, r)
+		return nil, nil, false
+	}
+
+	return collector, resp.Header, true
+}
@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	"github.com/devbrain/gateway/internal/middleware"
+)
+
+// backend는 프록시 대상 Backend 하나와 그 리버스 프록시
+type backend struct {
+	url   *url.URL
+	proxy *httputil.ReverseProxy
+
+	// 연결 실패 시 이 시각(UnixNano)까지 부하 분산 대상에서 제외
+	downUntil atomic.Int64
+
+	// 드레이닝 중이면 새 요청은 보내지 않고 진행 중인 요청(inflight)이 끝나기를 기다림
+	draining atomic.Bool
+	inflight atomic.Int64
+}
+
+// Backend 상태 (/health, /admin/diagnostics)
+const (
+	backendActive   = "active"
+	backendDraining = "draining"
+	backendDown     = "down"
+)
+
+// available은 Backend가 부하 분산 대상인지 확인
+func (b *backend) available(now time.Time) bool {
+	return !b.draining.Load() && now.UnixNano() >= b.downUntil.Load()
+}
+
+// state는 Backend의 현재 상태 (active / draining / down)
+func (b *backend) state(now time.Time) string {
+	switch {
+	case b.draining.Load():
+		return backendDraining
+	case now.UnixNano() < b.downUntil.Load():
+		return backendDown
+	default:
+		return backendActive
+	}
+}
+
+// track은 진행 중인 요청 수를 늘리고 끝났을 때 호출할 함수 반환 (드레이닝 완료 판단용)
+func (b *backend) track() func() {
+	b.inflight.Add(1)
+	return func() { b.inflight.Add(-1) }
+}
+
+// markDown은 Backend를 cooldown 동안 부하 분산 대상에서 제외
+func (b *backend) markDown(cooldown time.Duration) {
+	if cooldown <= 0 {
+		return
+	}
+	b.downUntil.Store(time.Now().Add(cooldown).UnixNano())
+	log.Printf("⚖️ Backend 일시 제외 (%v): %s", cooldown, b.url.Host)
+}
+
+// newBackend는 공통 훅(에러 처리, 요청 재작성, 응답 정리)이 설정된 backend 생성
+func (h *ProxyHandler) newBackend(target *url.URL) *backend {
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	proxy.Transport = h.transport
+
+	// 일반 /api/ 경로로 스트리밍하는 Backend를 위한 Flush 주기 설정
+	// (음수면 매 Write마다 즉시 Flush)
+	proxy.FlushInterval = time.Duration(h.config.ProxyFlushInterval) * time.Millisecond
+
+	b := &backend{url: target, proxy: proxy}
+
+	// 에러 핸들러 커스터마이징 (stale 캐시 / fallback 메시지)
+	// 클라이언트 취소가 아닌 연결 실패는 Backend를 드레이닝하고, 진행 중인 요청이 끝난 뒤 다시 확인하여
+	// 실패하면 BACKEND_DOWN_COOLDOWN 동안 부하 분산에서 제외
+	proxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+		if !errors.Is(err, context.Canceled) {
+			// 타임아웃은 연결 실패가 아니므로 드레이닝하지 않고 상태에만 반영
+			if !errors.Is(err, context.DeadlineExceeded) {
+				h.drain(b, err.Error())
+			}
+			h.observeProxy(r.Context(), true)
+		}
+		h.handleProxyError(w, r, err)
+	}
+
+	// Backend 요청 재작성 (인증 헤더 주입 등)
+	// serveProxy가 RemoteAddr를 결정된 클라이언트 IP로 바꿔 전달하므로, 기존 X-Forwarded-For를 지우면
+	// ReverseProxy가 그 IP 하나만으로 X-Forwarded-For를 다시 설정한다.
+	director := proxy.Director
+	proxy.Director = func(req *http.Request) {
+		director(req)
+		h.filterQueryParams(req)
+		h.rewriteBackendRequest(req, remoteHost(req.RemoteAddr))
+		req.Header.Del("X-Forwarded-For")
+	}
+
+	// Backend 응답 정리 (기본 설정에서는 no-op), 적응형 Rate Limiting용 상태 기록
+	proxy.ModifyResponse = func(resp *http.Response) error {
+		h.observeProxy(resp.Request.Context(), resp.StatusCode >= http.StatusInternalServerError)
+		return h.modifyResponse(resp)
+	}
+
+	return b
+}
+
+// backendKey는 허용 목록 비교를 위한 Backend 식별자 (scheme://host)
+func backendKey(u *url.URL) string {
+	return u.Scheme + "://" + u.Host
+}
+
+// selectBackend는 요청을 처리할 Backend 선택
+// 기본은 defaultBackend (쿼리 분류 또는 BACKEND_URLS 가중치 부하 분산)
+// 관리자 토큰이 있는 요청만 X-Backend-Target 헤더로 허용 목록의 Backend를 지정할 수 있으며,
+// 권한이 없거나 목록에 없는 값이면 헤더를 무시하고 기본 Backend 사용
+func (h *ProxyHandler) selectBackend(r *http.Request) *backend {
+	raw := r.Header.Get("X-Backend-Target")
+	if raw == "" || !h.isAdmin(r) {
+		return h.defaultBackend(r)
+	}
+
+	target, err := url.Parse(raw)
+	if err != nil {
+		return h.defaultBackend(r)
+	}
+
+	if b, ok := h.targets[backendKey(target)]; ok {
+		return b
+	}
+
+	log.Printf("⚠️ 허용되지 않은 Backend 지정 무시: %s", raw)
+	return h.defaultBackend(r)
+}
+
+// defaultBackend는 쿼리 분류로 지정된 Backend가 있으면 그것을,
+// 아니면 BACKEND_URLS 설정 시 가중치 부하 분산으로, 그 외에는 기본 Backend 반환
+func (h *ProxyHandler) defaultBackend(r *http.Request) *backend {
+	if b := routedBackend(r.Context()); b != nil {
+		return b
+	}
+	if h.pool != nil {
+		return h.pool.next()
+	}
+	return h.backend
+}
+
+// errBackendBusy는 Backend 동시 요청 슬롯을 제한 시간 내에 얻지 못한 경우의 에러
+var errBackendBusy = errors.New("backend concurrency limit reached")
+
+// backendLimiter는 Backend로 향하는 동시 요청 수를 제한하는 세마포어
+// 모든 Backend 호출 경로가 공유하여 Backend 쪽 backpressure를 한 곳에서 관리한다.
+type backendLimiter struct {
+	slots   chan struct{}
+	timeout time.Duration
+}
+
+// newBackendLimiter는 새로운 backendLimiter 생성 (max <= 0이면 nil = 무제한)
+func newBackendLimiter(max int, timeout time.Duration) *backendLimiter {
+	if max <= 0 {
+		return nil
+	}
+	return &backendLimiter{
+		slots:   make(chan struct{}, max),
+		timeout: timeout,
+	}
+}
+
+// acquire는 슬롯을 얻을 때까지 최대 timeout 동안 대기하고 반환 함수를 돌려줌
+func (bl *backendLimiter) acquire(ctx context.Context) (func(), error) {
+	if bl == nil {
+		return func() {}, nil
+	}
+
+	timer := time.NewTimer(bl.timeout)
+	defer timer.Stop()
+
+	select {
+	case bl.slots <- struct{}{}:
+		return func() { <-bl.slots }, nil
+	case <-timer.C:
+		return nil, errBackendBusy
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// serveProxy는 동시성 슬롯을 확보한 뒤 리버스 프록시로 요청 전달
+// BACKEND_TIMEOUT(또는 X-Request-Timeout)이 지나면 Backend 요청을 중단하고 504 응답
+func (h *ProxyHandler) serveProxy(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel, ok := h.backendContext(w, r, time.Duration(h.config.BackendTimeout)*time.Second)
+	if !ok {
+		return
+	}
+	defer cancel()
+	r = r.WithContext(ctx)
+
+	release, err := h.backendLimiter.acquire(r.Context())
+	if err != nil {
+		writeBackendBusy(w, r, err)
+		return
+	}
+	defer release()
+
+	b := h.selectBackend(r)
+	untrack := b.track()
+	defer untrack()
+
+	start := time.Now()
+	b.proxy.ServeHTTP(w, h.withClientAddr(withBackendStart(r, start)))
+
+	middleware.AddLogField(r.Context(), "backend", b.url.Host)
+	middleware.AddLogField(r.Context(), "backend_ms", time.Since(start).Milliseconds())
+}
+
+// writeBackendBusy는 슬롯 확보 실패 시 503 응답
+// 대기 중 클라이언트가 연결을 끊은 경우는 한도 초과가 아니므로 기록/응답하지 않음
+func writeBackendBusy(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	log.Printf("⚠️ Backend 동시 요청 한도 초과: %v", err)
+	middleware.WriteError(w, r, http.StatusServiceUnavailable, "백엔드 요청이 많아 처리할 수 없습니다. 잠시 후 다시 시도해주세요.")
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"bufio"
+	"io"
+	"sync/atomic"
+)
+
+// lineReader는 Backend 스트림을 별도 고루틴에서 줄 단위로 읽어 크기가 제한된 채널로 전달 (SSE_BUFFER_SIZE)
+// 클라이언트 전송이 느려 채널이 가득 차면 Backend 읽기를 멈추므로, 빠른 Backend와 느린 클라이언트 사이에서
+// Gateway가 쌓아 두는 양이 버퍼 크기로 제한되고 나머지는 Backend 쪽 TCP 흐름 제어로 넘어간다.
+// 버퍼는 바이트가 아니라 줄 수로 제한되며 한 줄은 Scanner 최대 크기(64 KiB)까지 가능하므로, 스트림당 최악의 경우
+// 메모리는 (bufferSize + 2) × 64 KiB (버퍼, 전송 대기 중인 줄, Scanner 내부 버퍼)다.
+type lineReader struct {
+	lines  chan string
+	stop   chan struct{}
+	err    error        // 읽기 오류 (lines가 닫힌 뒤에만 유효)
+	stalls atomic.Int64 // 채널이 가득 차서 클라이언트 전송을 기다린 횟수
+}
+
+// newLineReader는 body 읽기 고루틴을 시작 (bufferSize가 0이면 줄마다 전송을 기다림)
+func newLineReader(body io.Reader, bufferSize int) *lineReader {
+	lr := &lineReader{
+		lines: make(chan string, max(bufferSize, 0)),
+		stop:  make(chan struct{}),
+	}
+	go lr.run(body)
+	return lr
+}
+
+// run은 body를 끝까지 읽어 lines로 전달하고, 끝나면 lines를 닫음
+func (lr *lineReader) run(body io.Reader) {
+	defer close(lr.lines)
+
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		select {
+		case lr.lines <- line:
+			continue
+		default:
+		}
+
+		// 버퍼가 가득 참: 클라이언트가 따라올 때까지 Backend 읽기 중단
+		lr.stalls.Add(1)
+		select {
+		case lr.lines <- line:
+		case <-lr.stop:
+			return
+		}
+	}
+	lr.err = scanner.Err()
+}
+
+// close는 전송 쪽이 먼저 끝났을 때 읽기 고루틴 종료 (Backend 읽기에서 대기 중이면 응답 바디를 닫아야 끝남)
+func (lr *lineReader) close() {
+	close(lr.stop)
+}
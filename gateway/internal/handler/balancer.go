@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backendPool은 BACKEND_URLS로 설정된 여러 Backend 간 가중치 부하 분산
+// nginx의 smooth weighted round-robin을 사용하여 가중치 비율대로 고르게 분배하며,
+// 장애로 표시된(down) Backend는 건너뛴다.
+type backendPool struct {
+	mu      sync.Mutex
+	members []*poolMember
+}
+
+// poolMember는 풀에 속한 Backend와 가중치 상태
+type poolMember struct {
+	backend *backend
+	weight  int
+	current int
+}
+
+// parseWeightedBackend는 "http://host:port=3" 형식의 값을 URL과 가중치로 분리
+// 가중치가 없으면 1
+func parseWeightedBackend(raw string) (*url.URL, int, error) {
+	weight := 1
+	if i := strings.LastIndex(raw, "="); i > 0 {
+		if w, err := strconv.Atoi(raw[i+1:]); err == nil {
+			raw, weight = raw[:i], w
+		}
+	}
+
+	target, err := url.Parse(raw)
+	if err != nil {
+		return nil, 0, err
+	}
+	if target.Host == "" || weight <= 0 {
+		return nil, 0, strconv.ErrSyntax
+	}
+	return target, weight, nil
+}
+
+// newBackendPool은 BACKEND_URLS 값으로 backendPool 생성
+func (h *ProxyHandler) newBackendPool(entries []string) *backendPool {
+	pool := &backendPool{}
+	for _, raw := range entries {
+		target, weight, err := parseWeightedBackend(raw)
+		if err != nil {
+			log.Fatalf("❌ BACKEND_URLS 파싱 실패: %s", raw)
+		}
+		pool.members = append(pool.members, &poolMember{backend: h.newBackend(target), weight: weight})
+		log.Printf("⚖️ Backend 추가: %s (가중치 %d)", target, weight)
+	}
+	return pool
+}
+
+// next는 다음 요청을 처리할 Backend 선택
+// 정상 Backend가 하나도 없으면 장애 표시를 무시하고 전체에서 선택
+func (p *backendPool) next() *backend {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	if b := p.pick(func(m *poolMember) bool { return m.backend.available(now) }); b != nil {
+		return b
+	}
+	return p.pick(func(*poolMember) bool { return true })
+}
+
+// pick은 eligible인 멤버 중 smooth weighted round-robin으로 하나 선택
+func (p *backendPool) pick(eligible func(*poolMember) bool) *backend {
+	var best *poolMember
+	total := 0
+	for _, m := range p.members {
+		if !eligible(m) {
+			continue
+		}
+		m.current += m.weight
+		total += m.weight
+		if best == nil || m.current > best.current {
+			best = m
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	best.current -= total
+	return best.backend
+}
+
+// status는 진단 리포트용 풀 상태 (Backend별 가중치, 상태, 부하 분산 대상 여부)
+func (p *backendPool) status() []map[string]any {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	result := make([]map[string]any, 0, len(p.members))
+	for _, m := range p.members {
+		result = append(result, map[string]any{
+			"url":       m.backend.url.String(),
+			"weight":    m.weight,
+			"state":     m.backend.state(now),
+			"available": m.backend.available(now),
+		})
+	}
+	return result
+}
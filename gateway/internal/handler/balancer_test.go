@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/devbrain/gateway/internal/cache"
+	"github.com/devbrain/gateway/internal/config"
+)
+
+// newPoolHandler는 BACKEND_URLS 가중치 풀을 쓰는 ProxyHandler 생성 (요청은 보내지 않음)
+func newPoolHandler(t *testing.T, urls string) *ProxyHandler {
+	t.Helper()
+	t.Setenv("BACKEND_URLS", urls)
+	return NewProxyHandler("http://default.test", cache.NewMemoryCache(10), config.Load())
+}
+
+// countSelections는 n번 선택한 Backend 호스트별 횟수
+func countSelections(h *ProxyHandler, n int) map[string]int {
+	counts := map[string]int{}
+	for i := 0; i < n; i++ {
+		counts[h.selectBackend(httptest.NewRequest(http.MethodPost, "/api/chat", nil)).url.Host]++
+	}
+	return counts
+}
+
+func TestSelectBackendWeightedDistribution(t *testing.T) {
+	h := newPoolHandler(t, "http://a.test=3,http://b.test=1,http://c.test=2")
+
+	// smooth weighted round-robin은 가중치 합(6)만큼 선택할 때마다 정확히 가중치 비율로 분배
+	for round := 0; round < 3; round++ {
+		counts := countSelections(h, 6)
+		want := map[string]int{"a.test": 3, "b.test": 1, "c.test": 2}
+		for host, n := range want {
+			if counts[host] != n {
+				t.Fatalf("round %d: counts = %v, want %v", round, counts, want)
+			}
+		}
+	}
+
+	// 같은 Backend를 연달아 몰아주지 않고 고르게 섞음 (가중치 3도 연속 2회 이하)
+	prev, run := "", 0
+	for i := 0; i < 60; i++ {
+		host := h.selectBackend(httptest.NewRequest(http.MethodPost, "/api/chat", nil)).url.Host
+		if host == prev {
+			run++
+		} else {
+			prev, run = host, 1
+		}
+		if run > 2 {
+			t.Fatalf("%s selected %d times in a row", host, run)
+		}
+	}
+}
+
+func TestSelectBackendSkipsUnavailable(t *testing.T) {
+	h := newPoolHandler(t, "http://a.test=3,http://b.test=1")
+	for _, m := range h.pool.members {
+		if m.backend.url.Host == "a.test" {
+			m.backend.markDown(time.Minute)
+		}
+	}
+
+	if counts := countSelections(h, 8); counts["b.test"] != 8 {
+		t.Errorf("counts with a.test down = %v, want all b.test", counts)
+	}
+
+	// 모두 제외되면 장애 표시를 무시하고 가중치대로 선택
+	for _, m := range h.pool.members {
+		m.backend.markDown(time.Minute)
+	}
+	if counts := countSelections(h, 8); counts["a.test"] != 6 || counts["b.test"] != 2 {
+		t.Errorf("counts with all down = %v, want a.test=6 b.test=2", counts)
+	}
+}
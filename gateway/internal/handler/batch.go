@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+
+	"github.com/devbrain/gateway/internal/cache"
+	"github.com/devbrain/gateway/internal/middleware"
+)
+
+// batchRequest는 POST /api/chat/batch 요청 바디
+type batchRequest struct {
+	Queries []string `json:"queries"`
+}
+
+// batchResult는 배치 내 질문 하나의 결과
+type batchResult struct {
+	Query    string `json:"query"`
+	Response string `json:"response,omitempty"`
+	Cached   bool   `json:"cached"`
+	Error    string `json:"error,omitempty"`
+}
+
+// handleChatBatch는 여러 질문을 한 번에 처리 (캐시 적용)
+// 캐시 미스는 동기 채팅과 같은 flightGroup을 사용하므로 배치 안팎의 중복 질문은 Backend를 한 번만 호출한다.
+func (h *ProxyHandler) handleChatBatch(w http.ResponseWriter, r *http.Request) {
+	body, err := h.readRequestBody(r)
+	if err != nil {
+		writeBodyError(w, r, err)
+		return
+	}
+	if errs := h.validateBatchBody(body); len(errs) > 0 {
+		writeValidationError(w, r, errs)
+		return
+	}
+	if !h.validateRequestTimeout(w, r) {
+		return
+	}
+
+	var req batchRequest
+	if err := json.Unmarshal(body, &req); err != nil || len(req.Queries) == 0 {
+		middleware.WriteError(w, r, http.StatusBadRequest, "queries 배열이 필요합니다.")
+		return
+	}
+	if len(req.Queries) > h.config.BatchMaxQueries {
+		middleware.WriteError(w, r, http.StatusBadRequest, fmt.Sprintf("한 번에 최대 %d개까지 요청할 수 있습니다.", h.config.BatchMaxQueries))
+		return
+	}
+
+	// Backend에는 동기 채팅과 같은 형태의 요청으로 전달
+	chatReq := r.Clone(r.Context())
+	chatReq.Method = http.MethodPost
+	chatReq.URL.Path = "/api/chat"
+	chatReq.URL.RawPath = ""
+	chatReq.Header.Set("Content-Type", "application/json")
+
+	results := make([]batchResult, len(req.Queries))
+	var wg sync.WaitGroup
+	for i, query := range req.Queries {
+		wg.Add(1)
+		go func(i int, query string) {
+			defer wg.Done()
+			results[i] = h.answerBatchQuery(chatReq, query)
+		}(i, query)
+	}
+	wg.Wait()
+
+	log.Printf("📦 배치 처리: %d개 질문", len(req.Queries))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"results": results})
+}
+
+// answerBatchQuery는 배치 내 질문 하나를 캐시 또는 Backend로 처리
+func (h *ProxyHandler) answerBatchQuery(chatReq *http.Request, query string) batchResult {
+	if query == "" {
+		return batchResult{Query: query, Error: "empty query"}
+	}
+
+	// 결과에는 클라이언트가 보낸 질문을 그대로 표시하고, 캐시/Backend에는 마스킹된 쿼리 사용
+	chatReq, redacted := h.redactQuery(chatReq, query)
+	if cached, _ := h.getCached(chatReq.Context(), redacted); cached != nil {
+		return cachedBatchResult(query, cached)
+	}
+
+	body, _ := json.Marshal(map[string]string{h.config.QueryJSONField: h.backendQuery(chatReq.Context(), redacted)})
+	buf := h.fetchChat(h.routeQuery(chatReq, redacted), redacted, body)
+	if buf.hit != nil {
+		return cachedBatchResult(query, buf.hit)
+	}
+	if !h.cacheableStatus[buf.statusCode] {
+		return batchResult{Query: query, Error: fmt.Sprintf("backend status %d", buf.statusCode)}
+	}
+
+	answer, _, err := h.resolveAnswer(buf.body.Bytes())
+	if err != nil {
+		return batchResult{Query: query, Error: err.Error()}
+	}
+	return batchResult{Query: query, Response: answer}
+}
+
+// cachedBatchResult는 캐시 항목으로 배치 결과 생성 (404 네거티브 항목은 에러로 표시)
+func cachedBatchResult(query string, cached *cache.CachedResponse) batchResult {
+	if status := cached.StatusCode(); status != http.StatusOK {
+		return batchResult{Query: query, Error: fmt.Sprintf("backend status %d", status), Cached: true}
+	}
+	return batchResult{Query: query, Response: cached.Response, Cached: true}
+}
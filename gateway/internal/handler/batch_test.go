@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBatchCoalescesDuplicateQueries(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		calls = map[string]int{}
+	)
+	h := newTestHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Query string `json:"query"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		calls[body.Query]++
+		mu.Unlock()
+
+		// 중복 질문이 같은 호출을 기다리도록 응답을 늦춤
+		time.Sleep(50 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"response": "answer: " + body.Query})
+	}))
+
+	queries := []string{"중복 질문", "다른 질문", "중복 질문", "중복 질문"}
+	payload, _ := json.Marshal(map[string][]string{"queries": queries})
+	req := httptest.NewRequest(http.MethodPost, "/api/chat/batch", strings.NewReader(string(payload)))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Results []batchResult `json:"results"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Results) != len(queries) {
+		t.Fatalf("results = %d, want %d", len(resp.Results), len(queries))
+	}
+	for i, result := range resp.Results {
+		if result.Query != queries[i] || result.Response != "answer: "+queries[i] || result.Error != "" {
+			t.Errorf("results[%d] = %+v", i, result)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if calls["중복 질문"] != 1 || calls["다른 질문"] != 1 {
+		t.Errorf("backend calls = %v, want one per distinct query", calls)
+	}
+}
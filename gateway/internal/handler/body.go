@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/devbrain/gateway/internal/middleware"
+)
+
+// errBodyTooLarge는 압축 해제한 요청 바디가 GZIP_MAX_BODY_SIZE를 넘은 경우의 에러
+var errBodyTooLarge = errors.New("decompressed request body too large")
+
+// errInvalidGzip은 Content-Encoding: gzip 요청 바디를 해제할 수 없는 경우의 에러
+var errInvalidGzip = errors.New("invalid gzip request body")
+
+// readRequestBody는 채팅 요청 바디 읽기 (Content-Encoding: gzip이면 압축 해제)
+// 압축 해제한 경우 Content-Encoding 헤더를 지우고 r.Body/ContentLength를 해제된 바디로 교체하므로
+// 이후 Backend로 전달되는 요청은 압축되지 않은 바디를 사용한다.
+func (h *ProxyHandler) readRequestBody(r *http.Request) ([]byte, error) {
+	if !strings.EqualFold(strings.TrimSpace(r.Header.Get("Content-Encoding")), "gzip") {
+		return io.ReadAll(r.Body)
+	}
+
+	gz, err := gzip.NewReader(r.Body)
+	if err != nil {
+		return nil, errInvalidGzip
+	}
+	defer gz.Close()
+
+	// 한도보다 1바이트 더 읽어 초과 여부 판단
+	limit := int64(h.config.GzipMaxBodySize) << 20
+	body, err := io.ReadAll(io.LimitReader(gz, limit+1))
+	if err != nil {
+		return nil, errInvalidGzip
+	}
+	if int64(len(body)) > limit {
+		return nil, errBodyTooLarge
+	}
+
+	r.Header.Del("Content-Encoding")
+	r.ContentLength = int64(len(body))
+	return body, nil
+}
+
+// writeBodyError는 요청 바디 읽기 실패 응답 (압축 해제 한도 초과 413, 그 외 400)
+func writeBodyError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, errBodyTooLarge):
+		log.Printf("⚠️ 압축 해제한 요청 바디 크기 초과: %s %s", r.Method, r.URL.Path)
+		middleware.WriteError(w, r, http.StatusRequestEntityTooLarge, "압축 해제한 요청 바디가 너무 큽니다.")
+	case errors.Is(err, errInvalidGzip):
+		middleware.WriteError(w, r, http.StatusBadRequest, "gzip 요청 바디를 해제할 수 없습니다.")
+	default:
+		middleware.WriteError(w, r, http.StatusBadRequest, "")
+	}
+}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/devbrain/gateway/internal/cache"
+	"github.com/devbrain/gateway/internal/middleware"
+)
+
+// cachedResponseHeaders는 일반 응답 캐시에 함께 저장할 헤더
+var cachedResponseHeaders = []string{"Content-Type", "Content-Language"}
+
+// isCacheablePath는 CACHEABLE_PATHS에 포함된 일반 캐시 대상 경로인지 확인
+func (h *ProxyHandler) isCacheablePath(r *http.Request) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost && r.Method != http.MethodHead {
+		return false
+	}
+	for _, prefix := range h.config.CacheablePaths {
+		if strings.HasPrefix(r.URL.Path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// withRouteCachePolicy는 CACHE_DISABLED_PATHS 접두사에 해당하는 요청에 캐시 비활성화 표시를 추가
+func (h *ProxyHandler) withRouteCachePolicy(r *http.Request) *http.Request {
+	for _, prefix := range h.config.CacheDisabledPaths {
+		if strings.HasPrefix(r.URL.Path, prefix) {
+			return r.WithContext(context.WithValue(r.Context(), cacheDisabledContextKey, true))
+		}
+	}
+	return r
+}
+
+// cacheEnabled는 요청에 캐시를 사용할 수 있는지 확인 (CACHE_ENABLED이고 CACHE_DISABLED_PATHS 대상이 아닌 경우)
+func (h *ProxyHandler) cacheEnabled(ctx context.Context) bool {
+	disabled, _ := ctx.Value(cacheDisabledContextKey).(bool)
+	return h.config.CacheEnabled && !disabled
+}
+
+// handleCacheable은 전체 요청 기반 키로 일반 엔드포인트 응답을 캐싱
+func (h *ProxyHandler) handleCacheable(w http.ResponseWriter, r *http.Request) {
+	responses, ok := h.cache.(cache.ResponseStore)
+	if !ok || !h.cacheEnabled(r.Context()) || !h.cache.IsConnected() {
+		h.serveProxy(w, r)
+		return
+	}
+
+	// 키 계산을 위해 바디를 읽고 프록시를 위해 복원
+	var body []byte
+	if r.Body != nil {
+		var err error
+		body, err = io.ReadAll(r.Body)
+		if err != nil {
+			middleware.WriteError(w, r, http.StatusBadRequest, "")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	// HEAD는 같은 경로의 GET 응답 캐시를 조회
+	method := r.Method
+	if method == http.MethodHead {
+		method = http.MethodGet
+	}
+	key := cache.RequestKey(cacheTenant(r.Context()), method, r.URL.Path, r.URL.Query(), body)
+
+	cached, err := responses.GetResponse(key)
+	if err != nil {
+		cached = nil
+	}
+
+	if r.Method == http.MethodHead {
+		if cached != nil {
+			h.writeHeadHit(w, r, cached.Header.Get("Content-Type"), len(cached.Body))
+			return
+		}
+		h.writeHeadMiss(w, r)
+		return
+	}
+
+	if cached != nil {
+		log.Printf("💾 캐시 히트: %s %s", r.Method, r.URL.Path)
+		for _, name := range cachedResponseHeaders {
+			if value := cached.Header.Get(name); value != "" {
+				w.Header().Set(name, value)
+			}
+		}
+		setCacheStatus(w, r, "HIT")
+		w.WriteHeader(cached.Status)
+		w.Write(cached.Body)
+		return
+	}
+
+	// 캐시 키는 Accept-Encoding을 구분하지 않으므로 압축하지 않은 응답을 받아 저장
+	// (클라이언트의 Accept-Encoding을 전달하면 gzip 바디가 저장되어 요청하지 않은 클라이언트에게도 재생됨)
+	r = r.Clone(r.Context())
+	r.Header.Del("Accept-Encoding")
+
+	setCacheStatus(w, r, "MISS")
+	rec := &responseRecorder{
+		ResponseWriter: w,
+		body:           &bytes.Buffer{},
+	}
+	h.serveProxy(rec, r)
+
+	if rec.statusCode != http.StatusOK {
+		return
+	}
+	if encoding := rec.Header().Get("Content-Encoding"); encoding != "" && !strings.EqualFold(encoding, "identity") {
+		log.Printf("⏭️ 캐시 저장 안 함 (압축된 응답: %s): %s %s", encoding, r.Method, r.URL.Path)
+		return
+	}
+	ttl, store := h.backendCacheTTL(rec.Header())
+	if !store {
+		log.Printf("⏭️ 캐시 저장 안 함 (Backend Cache-Control): %s %s", r.Method, r.URL.Path)
+		return
+	}
+	if degraded := h.degradedReason(); degraded != "" {
+		log.Printf("⏭️ 캐시 저장 안 함 (Backend 불안정: %s): %s %s", degraded, r.Method, r.URL.Path)
+		return
+	}
+
+	header := http.Header{}
+	for _, name := range cachedResponseHeaders {
+		if value := rec.Header().Get(name); value != "" {
+			header.Set(name, value)
+		}
+	}
+
+	if err := responses.SetResponse(key, rec.statusCode, header, rec.body.Bytes(), ttl); err != nil {
+		log.Printf("⚠️ 캐시 저장 실패: %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+const cacheableBody = `{"documents":["RAG 소개","벡터 검색"]}`
+
+// getCacheable은 Accept-Encoding(빈 값이면 생략)과 함께 일반 캐시 경로를 요청하고 응답 반환
+func getCacheable(h http.Handler, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/docs", nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCacheableStoresUncompressedBody(t *testing.T) {
+	t.Setenv("CACHEABLE_PATHS", "/api/docs")
+	var calls atomic.Int64
+	h := newTestHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			io.WriteString(w, cacheableBody)
+			return
+		}
+		// 요청하면 gzip으로 응답하는 Backend
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		io.WriteString(gz, cacheableBody)
+		gz.Close()
+	}))
+
+	first := getCacheable(h, "gzip")
+	if first.Header().Get("X-Cache") != "MISS" || first.Body.String() != cacheableBody {
+		t.Fatalf("miss: X-Cache %q, body %q", first.Header().Get("X-Cache"), first.Body.String())
+	}
+	if encoding := first.Header().Get("Content-Encoding"); encoding != "" {
+		t.Errorf("miss Content-Encoding = %q, want uncompressed body", encoding)
+	}
+
+	for _, acceptEncoding := range []string{"", "gzip"} {
+		rec := getCacheable(h, acceptEncoding)
+		if rec.Header().Get("X-Cache") != "HIT" {
+			t.Fatalf("Accept-Encoding %q: X-Cache %q, want HIT", acceptEncoding, rec.Header().Get("X-Cache"))
+		}
+		if rec.Body.String() != cacheableBody || rec.Header().Get("Content-Encoding") != "" {
+			t.Errorf("Accept-Encoding %q: hit body %q (Content-Encoding %q), want plain JSON",
+				acceptEncoding, rec.Body.String(), rec.Header().Get("Content-Encoding"))
+		}
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("backend calls = %d, want 1", n)
+	}
+}
+
+func TestCacheableSkipsEncodedResponse(t *testing.T) {
+	t.Setenv("CACHEABLE_PATHS", "/api/docs")
+	var calls atomic.Int64
+	h := newTestHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		// 요청과 무관하게 압축하는 Backend (Transport가 해제하지 않는 형식)
+		w.Header().Set("Content-Encoding", "br")
+		w.Write([]byte{0x0b, 0x02, 0x80})
+	}))
+
+	getCacheable(h, "")
+	if rec := getCacheable(h, ""); rec.Header().Get("X-Cache") != "MISS" {
+		t.Errorf("X-Cache = %q, want MISS (encoded response not stored)", rec.Header().Get("X-Cache"))
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("backend calls = %d, want 2", n)
+	}
+}
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/devbrain/gateway/internal/cache"
+	"github.com/devbrain/gateway/internal/middleware"
+)
+
+// exportFlushEvery는 내보내기 중 클라이언트로 flush하는 항목 간격
+const exportFlushEvery = 100
+
+// importErrorLimit는 가져오기 응답에 포함하는 실패 항목 수 상한
+const importErrorLimit = 10
+
+// cacheExporter는 백업/복원을 지원하는 캐시 구현인지 확인하고, 아니면 501 응답
+func (h *ProxyHandler) cacheExporter(w http.ResponseWriter, r *http.Request) (cache.Exporter, bool) {
+	exporter, ok := h.cache.(cache.Exporter)
+	if !ok {
+		middleware.WriteError(w, r, http.StatusNotImplemented, "현재 캐시 백엔드는 백업/복원을 지원하지 않습니다.")
+	}
+	return exporter, ok
+}
+
+// handleCacheExport는 채팅 캐시 전체를 NDJSON(한 줄에 항목 하나)으로 스트리밍 (관리자 전용)
+// 백업과 Redis 인스턴스 간 이전용이며, 항목을 읽는 대로 전송하므로 캐시가 커도 메모리에 모으지 않는다.
+func (h *ProxyHandler) handleCacheExport(w http.ResponseWriter, r *http.Request) {
+	if !h.requireAdmin(w, r) {
+		return
+	}
+	exporter, ok := h.cacheExporter(w, r)
+	if !ok {
+		return
+	}
+
+	// 캐시가 크면 전송이 오래 걸리므로 SERVER_WRITE_TIMEOUT 쓰기 데드라인을 해제
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		log.Printf("⚠️ 캐시 내보내기 쓰기 데드라인 해제 실패: %v", err)
+	}
+
+	flusher, _ := w.(http.Flusher)
+	enc := json.NewEncoder(w)
+	count := 0
+
+	err := exporter.Export(r.Context(), func(entry *cache.ExportEntry) error {
+		if count == 0 {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="cache-%s.ndjson"`, time.Now().UTC().Format("20060102-150405")))
+			w.WriteHeader(http.StatusOK)
+		}
+		if err := enc.Encode(entry); err != nil {
+			return err
+		}
+		count++
+		if flusher != nil && count%exportFlushEvery == 0 {
+			flusher.Flush()
+		}
+		return nil
+	})
+
+	switch {
+	case err != nil && count == 0:
+		// 아직 아무 것도 보내지 않았으면 에러 응답 (Redis 장애 등)
+		log.Printf("❌ 캐시 내보내기 실패: %v", err)
+		middleware.WriteError(w, r, http.StatusServiceUnavailable, "캐시를 읽을 수 없습니다.")
+	case err != nil:
+		// 이미 200을 보냈으므로 중단만 기록 (받은 파일은 불완전)
+		log.Printf("❌ 캐시 내보내기 중단 (%d개 전송 후): %v", count, err)
+	case count == 0:
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(http.StatusOK)
+	default:
+		log.Printf("📦 캐시 내보내기: %d개", count)
+	}
+}
+
+// importResult는 캐시 가져오기 결과
+type importResult struct {
+	Imported int      `json:"imported"`
+	Skipped  int      `json:"skipped"` // 쿼리가 없거나 네거티브 캐시가 꺼져 있어 건너뛴 항목
+	Failed   int      `json:"failed"`
+	Errors   []string `json:"errors,omitempty"` // 처음 importErrorLimit개 실패 사유
+}
+
+// handleCacheImport는 /admin/cache/export가 만든 NDJSON을 읽는 대로 캐시에 저장 (관리자 전용)
+// 항목의 논리적 TTL은 현재 CACHE_TTL(네거티브 항목은 NEGATIVE_CACHE_TTL)로 다시 시작한다.
+func (h *ProxyHandler) handleCacheImport(w http.ResponseWriter, r *http.Request) {
+	if !h.requireAdmin(w, r) {
+		return
+	}
+	exporter, ok := h.cacheExporter(w, r)
+	if !ok {
+		return
+	}
+
+	ttl := time.Duration(h.config.CacheTTL) * time.Second
+	negativeTTL := time.Duration(h.config.NegativeCacheTTL) * time.Second
+
+	// 큰 백업 파일을 읽는 동안 SERVER_READ_TIMEOUT에 끊기지 않도록 읽기 데드라인 해제
+	if err := http.NewResponseController(w).SetReadDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		log.Printf("⚠️ 캐시 가져오기 읽기 데드라인 해제 실패: %v", err)
+	}
+
+	var result importResult
+	dec := json.NewDecoder(r.Body)
+	for line := 1; ; line++ {
+		var entry cache.ExportEntry
+		err := dec.Decode(&entry)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			// 줄 경계를 잃었으므로 더 읽지 않음 (이미 저장한 항목은 유지)
+			middleware.WriteError(w, r, http.StatusBadRequest,
+				fmt.Sprintf("%d번째 항목을 해석할 수 없습니다 (앞의 %d개는 저장됨): %v", line, result.Imported, err))
+			return
+		}
+
+		entryTTL := ttl
+		if entry.CachedResponse != nil && entry.Negative {
+			entryTTL = negativeTTL
+		}
+		if entry.CachedResponse == nil || entry.Query == "" || entryTTL <= 0 {
+			result.Skipped++
+			continue
+		}
+
+		if err := exporter.Import(r.Context(), &entry, entryTTL); err != nil {
+			result.Failed++
+			if len(result.Errors) < importErrorLimit {
+				result.Errors = append(result.Errors, fmt.Sprintf("%d: %v", line, err))
+			}
+			continue
+		}
+		result.Imported++
+	}
+
+	log.Printf("📦 캐시 가져오기: 저장 %d개, 건너뜀 %d개, 실패 %d개", result.Imported, result.Skipped, result.Failed)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
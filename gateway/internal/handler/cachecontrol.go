@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// backendCacheTTL은 Backend 응답의 Cache-Control에 따라 캐시 TTL 결정 (BACKEND_CACHE_CONTROL)
+//   - no-store, private, no-cache: 저장하지 않음 (store=false)
+//     Gateway는 쿼리로 키를 만드는 공유 캐시이므로 사용자별 답변(private)이나 재검증이 필요한 답변(no-cache)을
+//     다른 사용자에게 재사용하지 않는다.
+//   - s-maxage / max-age: 그 값을 TTL로 사용 (BACKEND_CACHE_MAX_TTL로 상한, 0이면 저장하지 않음)
+//   - 지시어가 없거나 비활성화된 경우: CACHE_TTL
+func (h *ProxyHandler) backendCacheTTL(header http.Header) (ttl time.Duration, store bool) {
+	ttl = time.Duration(h.config.CacheTTL) * time.Second
+	if !h.config.BackendCacheControl || header == nil {
+		return ttl, true
+	}
+
+	maxAge, sharedMaxAge := -1, -1
+	for _, value := range header.Values("Cache-Control") {
+		for _, directive := range strings.Split(value, ",") {
+			name, arg, _ := strings.Cut(strings.TrimSpace(directive), "=")
+			switch strings.ToLower(name) {
+			case "no-store", "private", "no-cache":
+				return 0, false
+			case "max-age":
+				maxAge = parseDeltaSeconds(arg)
+			case "s-maxage":
+				sharedMaxAge = parseDeltaSeconds(arg)
+			}
+		}
+	}
+
+	// Gateway는 공유 캐시이므로 s-maxage가 max-age보다 우선
+	seconds := maxAge
+	if sharedMaxAge >= 0 {
+		seconds = sharedMaxAge
+	}
+	switch {
+	case seconds < 0:
+		return ttl, true
+	case seconds == 0:
+		return 0, false
+	}
+
+	ttl = time.Duration(seconds) * time.Second
+	if limit := time.Duration(h.config.BackendCacheMaxTTL) * time.Second; limit > 0 && ttl > limit {
+		ttl = limit
+	}
+	return ttl, true
+}
+
+// parseDeltaSeconds는 max-age 값 파싱 (잘못된 값이면 -1 = 지시어 없음)
+func parseDeltaSeconds(arg string) int {
+	seconds, err := strconv.Atoi(strings.Trim(strings.TrimSpace(arg), `"`))
+	if err != nil || seconds < 0 {
+		return -1
+	}
+	return seconds
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/devbrain/gateway/internal/cache"
+	"github.com/devbrain/gateway/internal/middleware"
+)
+
+// clientCacheKeyHeader는 클라이언트가 채팅 캐시 키를 직접 지정하는 요청 헤더 (CLIENT_CACHE_KEYS)
+// 응답의 X-Cache-Key(CACHE_DEBUG)와 이름이 같으며, 지정한 경우 응답에는 실제 Redis 키가 표시된다.
+const clientCacheKeyHeader = "X-Cache-Key"
+
+// clientCacheKeyPattern은 허용하는 클라이언트 캐시 키 (영문/숫자/._- 1~128자)
+var clientCacheKeyPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// withClientCacheKey는 CLIENT_CACHE_KEYS 설정 시 X-Cache-Key 헤더를 검증하여 요청 컨텍스트에 저장
+// 값이 잘못되었으면 400 응답을 쓰고 false 반환 (기능이 꺼져 있거나 헤더가 없으면 그대로 진행)
+func (h *ProxyHandler) withClientCacheKey(w http.ResponseWriter, r *http.Request) (*http.Request, bool) {
+	key := r.Header.Get(clientCacheKeyHeader)
+	if !h.config.ClientCacheKeys || key == "" {
+		return r, true
+	}
+	if !clientCacheKeyPattern.MatchString(key) {
+		middleware.WriteError(w, r, http.StatusBadRequest, "X-Cache-Key must be 1-128 characters of [A-Za-z0-9._-]")
+		return r, false
+	}
+
+	middleware.AddLogField(r.Context(), "cache_key", key)
+	return r.WithContext(context.WithValue(r.Context(), clientCacheKeyContextKey, key)), true
+}
+
+// cacheQuery는 캐시 조회/저장에 사용할 쿼리 (클라이언트가 키를 지정했으면 그 키의 전용 공간, 테넌트가 있으면 테넌트 공간)
+func cacheQuery(ctx context.Context, query string) string {
+	if key, ok := ctx.Value(clientCacheKeyContextKey).(string); ok {
+		query = cache.ClientKeyQuery(key)
+	}
+	if tenant := cacheTenant(ctx); tenant != "" {
+		query = cache.TenantQuery(tenant, query)
+	}
+	return query
+}
+
+// scopedCacheKey는 요청이 전용 키 공간(클라이언트 지정 키 또는 테넌트)을 사용하는지 확인
+// 시맨틱 캐시는 키 공간 구분 없이 질문을 비교하므로 이 경우 사용하지 않음
+func scopedCacheKey(ctx context.Context) bool {
+	_, ok := ctx.Value(clientCacheKeyContextKey).(string)
+	return ok || cacheTenant(ctx) != ""
+}
+
+// 캐시 테넌트 식별 방식 (CACHE_TENANT)
+const (
+	cacheTenantHeader = "header"  // CACHE_TENANT_HEADER 값
+	cacheTenantAPIKey = "api_key" // 인증된 API 키 (해시)
+)
+
+// tenantPattern은 허용하는 테넌트 헤더 값 (영문/숫자/._- 1~64자, 키 이름에 그대로 포함되므로 제한)
+var tenantPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// withCacheTenant는 CACHE_TENANT 설정 시 요청의 테넌트 식별자를 결정하여 요청 컨텍스트에 저장
+// 캐시를 사용하는 라우트에 테넌트가 없으면(헤더 없음, 익명) 공용 키 공간으로 다른 테넌트의 답변을 받지 않도록
+// 거부한다 (header: 400, api_key: 401). 헤더 값이 잘못되었어도 400. 응답을 썼으면 false 반환
+func (h *ProxyHandler) withCacheTenant(w http.ResponseWriter, r *http.Request) (*http.Request, bool) {
+	var tenant string
+	switch h.config.CacheTenant {
+	case cacheTenantHeader:
+		tenant = r.Header.Get(h.config.CacheTenantHeader)
+		if tenant != "" && !tenantPattern.MatchString(tenant) {
+			middleware.WriteError(w, r, http.StatusBadRequest, h.config.CacheTenantHeader+" must be 1-64 characters of [A-Za-z0-9._-]")
+			return r, false
+		}
+	case cacheTenantAPIKey:
+		// API 키가 Redis 키 이름에 노출되지 않도록 해시 앞부분만 사용
+		if identity := middleware.IdentityFromContext(r.Context()); identity.Authenticated {
+			sum := sha256.Sum256([]byte(identity.APIKey))
+			tenant = "key-" + hex.EncodeToString(sum[:8])
+		}
+	}
+	if tenant != "" {
+		return r.WithContext(context.WithValue(r.Context(), cacheTenantContextKey, tenant)), true
+	}
+
+	switch {
+	case h.config.CacheTenant == "" || !h.tenantScopedRoute(r):
+		return r, true
+	case h.config.CacheTenant == cacheTenantAPIKey:
+		middleware.WriteError(w, r, http.StatusUnauthorized, "API key is required for tenant-scoped cache")
+	default:
+		middleware.WriteError(w, r, http.StatusBadRequest, h.config.CacheTenantHeader+" header is required")
+	}
+	return r, false
+}
+
+// tenantScopedRoute는 테넌트별 캐시 키 공간을 사용하는 라우트인지 확인 (채팅과 CACHEABLE_PATHS)
+func (h *ProxyHandler) tenantScopedRoute(r *http.Request) bool {
+	path := r.URL.Path
+	switch {
+	case path == "/api/chat" || path == "/api/chat/stream" || path == "/api/chat/batch":
+		return true
+	case exactRoutes[path]:
+		return false
+	}
+	return strings.HasPrefix(path, "/api/") && h.isCacheablePath(r)
+}
+
+// cacheTenant는 요청의 캐시 테넌트 (CACHE_TENANT가 꺼져 있거나 테넌트가 없으면 빈 문자열)
+func cacheTenant(ctx context.Context) string {
+	tenant, _ := ctx.Value(cacheTenantContextKey).(string)
+	return tenant
+}
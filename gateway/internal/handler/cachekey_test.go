@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// tenantChat은 테넌트 헤더(빈 값이면 생략)와 함께 동기 채팅 요청을 보내고 응답 반환
+func tenantChat(h http.Handler, tenant, query string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(map[string]string{"query": query})
+	req := httptest.NewRequest(http.MethodPost, "/api/chat", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	if tenant != "" {
+		req.Header.Set("X-Tenant-ID", tenant)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCacheTenantHeaderRequired(t *testing.T) {
+	t.Setenv("CACHE_TENANT", "header")
+	var calls atomic.Int64
+	h := newTestHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"response": "답변"})
+	}))
+
+	if rec := tenantChat(h, "", "테넌트 질문"); rec.Code != http.StatusBadRequest {
+		t.Errorf("missing tenant: status %d, want 400", rec.Code)
+	}
+	if rec := tenantChat(h, "bad tenant!", "테넌트 질문"); rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid tenant: status %d, want 400", rec.Code)
+	}
+	if text, rec := getStream(h, "테넌트 질문"); rec.Code != http.StatusBadRequest {
+		t.Errorf("stream missing tenant: status %d, text %q, want 400", rec.Code, text)
+	}
+	if n := calls.Load(); n != 0 {
+		t.Errorf("backend calls = %d, want 0 for rejected requests", n)
+	}
+
+	if rec := tenantChat(h, "team-a", "테넌트 질문"); rec.Code != http.StatusOK || rec.Header().Get("X-Cache") != "MISS" {
+		t.Fatalf("team-a: status %d, X-Cache %q", rec.Code, rec.Header().Get("X-Cache"))
+	}
+	if rec := tenantChat(h, "team-b", "테넌트 질문"); rec.Header().Get("X-Cache") != "MISS" {
+		t.Errorf("team-b X-Cache = %q, want MISS (separate key space)", rec.Header().Get("X-Cache"))
+	}
+	if rec := tenantChat(h, "team-a", "테넌트 질문"); rec.Header().Get("X-Cache") != "HIT" {
+		t.Errorf("team-a repeat X-Cache = %q, want HIT", rec.Header().Get("X-Cache"))
+	}
+
+	// 캐시를 사용하지 않는 라우트는 테넌트 없이도 처리
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz/live", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("liveness without tenant: status %d, want 200", rec.Code)
+	}
+}
+
+func TestCacheTenantAPIKeyRequiresAuthentication(t *testing.T) {
+	t.Setenv("CACHE_TENANT", "api_key")
+	h := newTestHandler(t, http.NotFoundHandler())
+
+	if rec := tenantChat(h, "", "익명 질문"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("anonymous: status %d, want 401", rec.Code)
+	}
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/devbrain/gateway/internal/cache"
+)
+
+// SyncCacheVersion은 Backend 버전을 캐시 키 버전으로 사용 (CACHE_VERSION=backend)
+// 시작 시 한 번 조회하고, interval이 0보다 크면 주기적으로 다시 조회하여 모델 배포 시 캐시 키 공간을 바꾼다.
+func (h *ProxyHandler) SyncCacheVersion(ctx context.Context, interval time.Duration) {
+	if _, ok := h.cache.(cache.KeyVersioner); !ok {
+		log.Println("⚠️ 캐시 키 버전을 지원하지 않는 캐시 구현, CACHE_VERSION=backend 무시")
+		return
+	}
+	h.refreshCacheVersion(ctx)
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				h.refreshCacheVersion(ctx)
+			}
+		}
+	}()
+}
+
+// refreshCacheVersion은 Backend 버전을 조회하고 바뀌었으면 캐시 키 버전 갱신
+// 조회 실패 시 기존 버전 유지
+func (h *ProxyHandler) refreshCacheVersion(ctx context.Context) {
+	versioner := h.cache.(cache.KeyVersioner)
+	version, err := h.fetchBackendVersion(ctx)
+	if err != nil {
+		log.Printf("⚠️ Backend 버전 조회 실패 (캐시 버전 유지: %q): %v", versioner.KeyVersion(), err)
+		return
+	}
+
+	if previous := versioner.KeyVersion(); version != previous {
+		versioner.SetKeyVersion(version)
+		log.Printf("🏷️ 캐시 키 버전 변경: %q → %q", previous, version)
+	}
+}
+
+// fetchBackendVersion은 BACKEND_VERSION_PATH 응답에서 버전 문자열 추출
+// JSON이면 "version" 필드, 아니면 응답 바디 전체를 사용
+func (h *ProxyHandler) fetchBackendVersion(ctx context.Context) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, backendProbeTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.backend.url.String()+h.config.BackendVersionPath, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected backend version status: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return "", err
+	}
+
+	var info struct {
+		Version string `json:"version"`
+	}
+	if json.Unmarshal(body, &info) == nil && info.Version != "" {
+		return info.Version, nil
+	}
+
+	version := strings.TrimSpace(string(body))
+	if version == "" || strings.HasPrefix(version, "{") {
+		return "", fmt.Errorf("backend version not found in response")
+	}
+	return version, nil
+}
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/devbrain/gateway/internal/middleware"
+)
+
+// queryRouteRule은 QUERY_ROUTES_FILE의 분류 규칙 하나
+// keywords(대소문자 무시 부분 일치) 또는 pattern(정규식) 중 하나라도 맞으면 해당 분류
+type queryRouteRule struct {
+	Category string   `json:"category"`
+	Keywords []string `json:"keywords"`
+	Pattern  string   `json:"pattern"`
+	Backend  string   `json:"backend"`
+}
+
+// queryRoute는 컴파일된 분류 규칙
+type queryRoute struct {
+	category string
+	keywords []string
+	pattern  *regexp.Regexp
+	backend  *backend
+}
+
+// matches는 쿼리가 규칙에 맞는지 확인
+func (qr *queryRoute) matches(query string) bool {
+	lower := strings.ToLower(query)
+	for _, keyword := range qr.keywords {
+		if strings.Contains(lower, keyword) {
+			return true
+		}
+	}
+	return qr.pattern != nil && qr.pattern.MatchString(query)
+}
+
+// queryRouter는 채팅 쿼리를 분류하여 Backend를 선택 (규칙 순서대로 처음 맞는 규칙 사용)
+type queryRouter struct {
+	routes []*queryRoute
+}
+
+// loadQueryRouter는 규칙 파일(JSON 배열)을 읽어 queryRouter 생성
+func (h *ProxyHandler) loadQueryRouter(path string) (*queryRouter, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []queryRouteRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, err
+	}
+
+	router := &queryRouter{}
+	for i, rule := range rules {
+		if rule.Category == "" || rule.Backend == "" {
+			return nil, fmt.Errorf("rule %d: category and backend are required", i)
+		}
+
+		route := &queryRoute{category: rule.Category}
+		for _, keyword := range rule.Keywords {
+			route.keywords = append(route.keywords, strings.ToLower(keyword))
+		}
+		if rule.Pattern != "" {
+			if route.pattern, err = regexp.Compile(rule.Pattern); err != nil {
+				return nil, fmt.Errorf("rule %d (%s): %w", i, rule.Category, err)
+			}
+		}
+
+		target, err := url.Parse(rule.Backend)
+		if err != nil || target.Host == "" {
+			return nil, fmt.Errorf("rule %d (%s): invalid backend %q", i, rule.Category, rule.Backend)
+		}
+		route.backend = h.backendFor(target)
+
+		router.routes = append(router.routes, route)
+		log.Printf("🧭 쿼리 분류 규칙: %s → %s", rule.Category, target)
+	}
+	return router, nil
+}
+
+// backendFor는 이미 등록된 Backend(기본, BACKEND_TARGETS, BACKEND_URLS)가 있으면 재사용하고 없으면 생성
+func (h *ProxyHandler) backendFor(target *url.URL) *backend {
+	key := backendKey(target)
+	if backendKey(h.backend.url) == key {
+		return h.backend
+	}
+	if b, ok := h.targets[key]; ok {
+		return b
+	}
+	if h.pool != nil {
+		for _, m := range h.pool.members {
+			if backendKey(m.backend.url) == key {
+				return m.backend
+			}
+		}
+	}
+	return h.newBackend(target)
+}
+
+// match는 쿼리에 맞는 규칙 반환 (없으면 nil)
+func (qr *queryRouter) match(query string) *queryRoute {
+	if qr == nil {
+		return nil
+	}
+	for _, route := range qr.routes {
+		if route.matches(query) {
+			return route
+		}
+	}
+	return nil
+}
+
+// classify는 쿼리 분류 반환 (맞는 규칙이 없으면 빈 문자열 = 기본 Backend)
+func (qr *queryRouter) classify(query string) string {
+	if route := qr.match(query); route != nil {
+		return route.category
+	}
+	return ""
+}
+
+// routeQuery는 쿼리를 분류하여 요청 컨텍스트에 Backend를 지정하고 접근 로그에 분류를 남김
+func (h *ProxyHandler) routeQuery(r *http.Request, query string) *http.Request {
+	route := h.queryRouter.match(query)
+	if route == nil {
+		return r
+	}
+	middleware.AddLogField(r.Context(), "category", route.category)
+	return withRoutedBackend(r, route.backend)
+}
+
+// withRoutedBackend는 요청 컨텍스트에 쿼리 분류로 선택된 Backend를 저장
+func withRoutedBackend(r *http.Request, b *backend) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), backendContextKey, b))
+}
+
+// routedBackend는 요청 컨텍스트에 저장된 분류 Backend 반환 (없으면 nil)
+func routedBackend(ctx context.Context) *backend {
+	b, _ := ctx.Value(backendContextKey).(*backend)
+	return b
+}
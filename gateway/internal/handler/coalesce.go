@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/devbrain/gateway/internal/cache"
+)
+
+// lockPollInterval은 캐시 잠금 대기 중 캐시를 다시 확인하는 간격
+const lockPollInterval = 100 * time.Millisecond
+
+// flightGroup은 같은 키의 동시 호출을 하나로 합치는 singleflight 구현
+// 먼저 도착한 호출(leader)만 fn을 실행하고, 나머지는 그 결과를 공유한다.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+// flightCall은 진행 중인 호출
+type flightCall struct {
+	key    string
+	done   chan struct{}
+	result *bufferedResponse
+
+	// 결과를 기다리는 호출자 수 (g.mu 보호), 모두 연결을 끊으면 cancel로 호출 자체를 취소
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// do는 key에 대해 진행 중인 호출이 있으면 그 결과를 기다리고, 없으면 fn 실행
+// 결과를 다른 호출과 공유했으면 shared가 true
+// fn에 전달되는 컨텍스트는 호출자 하나가 끊겨도 유지되고, 기다리는 호출자가 모두 끊기면 취소된다.
+func (g *flightGroup) do(ctx context.Context, key string, fn func(ctx context.Context) *bufferedResponse) (result *bufferedResponse, shared bool) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+	if call, ok := g.calls[key]; ok {
+		call.waiters++
+		g.mu.Unlock()
+
+		stop := context.AfterFunc(ctx, func() { g.leave(call) })
+		defer stop()
+		<-call.done
+		return call.result, true
+	}
+
+	callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	call := &flightCall{key: key, done: make(chan struct{}), waiters: 1, cancel: cancel}
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	stop := context.AfterFunc(ctx, func() { g.leave(call) })
+	defer func() {
+		stop()
+		g.mu.Lock()
+		if g.calls[key] == call {
+			delete(g.calls, key)
+		}
+		g.mu.Unlock()
+		close(call.done)
+		cancel()
+	}()
+
+	call.result = fn(callCtx)
+	return call.result, false
+}
+
+// leave는 호출자 하나가 연결을 끊었음을 기록하고, 남은 호출자가 없으면 호출 취소
+// 취소된 호출은 그룹에서 바로 빼서 이후 같은 키의 요청은 새로 호출한다.
+func (g *flightGroup) leave(call *flightCall) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	call.waiters--
+	if call.waiters > 0 {
+		return
+	}
+	if g.calls[call.key] == call {
+		delete(g.calls, call.key)
+	}
+	call.cancel()
+	log.Printf("🛑 모든 클라이언트 연결 종료, Backend 호출 취소: %s", call.key)
+}
+
+// lockCompute는 CACHE_LOCK_TTL 설정 시 인스턴스 간 계산 잠금을 획득
+// 다른 인스턴스가 잠금을 갖고 있으면 CACHE_LOCK_WAIT 동안 캐시를 다시 확인하고,
+// 그 사이 저장된 응답이 있으면 반환. 끝내 없으면 잠금 없이 직접 계산하도록 빈 release 반환
+func (h *ProxyHandler) lockCompute(ctx context.Context, query string) (release func(), cached *cache.CachedResponse) {
+	noop := func() {}
+	locker, ok := h.cache.(cache.Locker)
+	if !ok || h.config.CacheLockTTL <= 0 || !h.cacheEnabled(ctx) {
+		return noop, nil
+	}
+
+	key := h.cache.Key(cacheQuery(ctx, query))
+	release, acquired, err := locker.TryLock(ctx, key, time.Duration(h.config.CacheLockTTL)*time.Second)
+	if err != nil {
+		log.Printf("⚠️ 캐시 잠금 실패 (잠금 없이 진행): %v", err)
+		return noop, nil
+	}
+	if acquired {
+		return release, nil
+	}
+
+	// 다른 인스턴스가 계산 중: 캐시에 저장될 때까지 대기
+	deadline := time.Now().Add(time.Duration(h.config.CacheLockWait) * time.Millisecond)
+	for time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			return noop, nil
+		case <-time.After(lockPollInterval):
+		}
+
+		if cached, err := h.cache.Get(cacheQuery(ctx, query)); err == nil && cached != nil {
+			log.Printf("🔒 다른 인스턴스 계산 결과 사용: %s", query[:min(30, len(query))])
+			return noop, cached
+		}
+	}
+
+	log.Printf("🔒 캐시 잠금 대기 시간 초과, 직접 계산: %s", query[:min(30, len(query))])
+	return noop, nil
+}
+
+// flightKey는 요청 합치기 키 (캐시 키, X-Request-Timeout이 있으면 적용되는 타임아웃 포함)
+// leader의 타임아웃이 함께 기다리는 요청에도 적용되므로, 타임아웃이 다른 요청끼리는 합치지 않는다.
+// 원본 쿼리를 Backend에 전달하는 마스킹 요청은 원본 해시도 포함해 원본이 같은 요청끼리만 합친다.
+func (h *ProxyHandler) flightKey(r *http.Request, query string) string {
+	key := h.cache.Key(cacheQuery(r.Context(), query))
+	if hash := h.originalQueryHash(r.Context()); hash != "" {
+		key += "#original=" + hash
+	}
+	if timeout, err := h.backendTimeout(r, 0); err == nil && timeout > 0 {
+		key += "#timeout=" + timeout.String()
+	}
+	return key
+}
+
+// fetchChat은 동기 채팅 Backend 호출을 캐시 키 단위로 합쳐서 실행
+// /api/chat과 /api/chat/batch가 같은 그룹을 사용하므로 요청 안팎의 중복 질문이 한 번만 호출된다.
+// 호출 결과는 leader가 한 번만 캐시에 저장하고, 응답은 버퍼링되어 모든 호출자에게 공유된다.
+func (h *ProxyHandler) fetchChat(r *http.Request, query string, body []byte) *bufferedResponse {
+	result, shared := h.flight.do(r.Context(), h.flightKey(r, query), func(ctx context.Context) *bufferedResponse {
+		// leader 클라이언트가 끊겨도 결과를 기다리는 다른 호출자가 있으면 계속 진행하고,
+		// 모두 끊기면 ctx가 취소되어 Backend 호출도 중단됨
+		req := withQuery(r.Clone(ctx), query)
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.ContentLength = int64(len(body))
+		req.GetBody = func() (io.ReadCloser, error) { // 307/308 리다이렉트를 따라갈 때 바디 재전송 (FOLLOW_REDIRECTS)
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+
+		// 다른 Gateway 인스턴스가 같은 질문을 계산 중이면 잠시 기다렸다가 그 결과를 캐시에서 사용
+		release, cached := h.lockCompute(req.Context(), query)
+		if cached != nil {
+			return &bufferedResponse{hit: cached}
+		}
+		defer release()
+
+		buf := newBufferedResponse()
+		if h.config.SyncViaStream {
+			// 스트림 전용 Backend: SSE 토큰을 모아 동기 응답으로 변환 (캐시 저장 포함)
+			h.fetchViaStream(buf, req, query)
+			return buf
+		}
+		h.serveProxy(buf, req)
+		h.cacheSyncResponse(ctx, query, buf.statusCode, buf.header, buf.body.Bytes())
+		return buf
+	})
+	if shared {
+		log.Printf("🔗 중복 요청 합침: %s", query[:min(30, len(query))])
+	}
+	return result
+}
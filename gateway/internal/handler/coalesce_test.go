@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// startLeader는 fn이 시작될 때까지 기다린 뒤 leader 호출의 결과 채널과 fn 컨텍스트 반환
+// fn은 release가 닫히거나 컨텍스트가 취소될 때까지 실행된다.
+func startLeader(t *testing.T, g *flightGroup, ctx context.Context, release <-chan struct{}) (<-chan *bufferedResponse, context.Context) {
+	t.Helper()
+	started := make(chan context.Context, 1)
+	results := make(chan *bufferedResponse, 1)
+	go func() {
+		result, _ := g.do(ctx, "key", func(callCtx context.Context) *bufferedResponse {
+			started <- callCtx
+			select {
+			case <-release:
+			case <-callCtx.Done():
+			}
+			return newBufferedResponse()
+		})
+		results <- result
+	}()
+
+	select {
+	case callCtx := <-started:
+		return results, callCtx
+	case <-time.After(time.Second):
+		t.Fatal("leader fn did not start")
+		return nil, nil
+	}
+}
+
+// joinWaiter는 같은 키의 호출에 합류하고 결과 채널 반환 (합류할 때까지 대기)
+func joinWaiter(t *testing.T, g *flightGroup, ctx context.Context) <-chan *bufferedResponse {
+	t.Helper()
+	results := make(chan *bufferedResponse, 1)
+	go func() {
+		result, shared := g.do(ctx, "key", func(context.Context) *bufferedResponse {
+			t.Error("waiter ran its own call")
+			return nil
+		})
+		if !shared {
+			t.Error("waiter result not shared")
+		}
+		results <- result
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		g.mu.Lock()
+		waiters := g.calls["key"].waiters
+		g.mu.Unlock()
+		if waiters >= 2 {
+			return results
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("waiter did not join")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestFlightGroupLeaderCancelKeepsCallForWaiters(t *testing.T) {
+	var g flightGroup
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	release := make(chan struct{})
+
+	leaderResult, callCtx := startLeader(t, &g, leaderCtx, release)
+	waiterResult := joinWaiter(t, &g, context.Background())
+
+	cancelLeader()
+	select {
+	case <-callCtx.Done():
+		t.Fatal("call cancelled while a waiter is still waiting")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	shared := <-waiterResult
+	if shared == nil || shared != <-leaderResult {
+		t.Error("waiter did not receive the leader's result")
+	}
+}
+
+func TestFlightGroupAllCallersCancelStopsCall(t *testing.T) {
+	var g flightGroup
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	waiterCtx, cancelWaiter := context.WithCancel(context.Background())
+
+	_, callCtx := startLeader(t, &g, leaderCtx, nil)
+	joinWaiter(t, &g, waiterCtx)
+
+	cancelWaiter()
+	cancelLeader()
+	select {
+	case <-callCtx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("call not cancelled after all callers left")
+	}
+
+	// 취소된 호출은 그룹에서 빠지므로 같은 키의 새 요청은 새로 호출
+	g.mu.Lock()
+	_, inflight := g.calls["key"]
+	g.mu.Unlock()
+	if inflight {
+		t.Error("cancelled call still registered")
+	}
+}
@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/devbrain/gateway/internal/middleware"
+)
+
+// drainPollInterval은 드레이닝 중인 Backend의 진행 중인 요청 수를 확인하는 간격
+const drainPollInterval = 500 * time.Millisecond
+
+// drain은 Backend를 드레이닝 상태로 전환 (이미 드레이닝 중이면 false)
+// 새 요청은 다른 Backend로 보내고, 진행 중인 요청(스트림 포함)이 끝나면 헬스체크로 다시 확인한다.
+func (h *ProxyHandler) drain(b *backend, reason string) bool {
+	if !b.draining.CompareAndSwap(false, true) {
+		return false
+	}
+	log.Printf("🚰 Backend 드레이닝 시작: %s (%s, 진행 중 %d)", b.url.Host, reason, b.inflight.Load())
+	go h.finishDrain(b)
+	return true
+}
+
+// finishDrain은 진행 중인 요청이 모두 끝나거나 BACKEND_DRAIN_TIMEOUT이 지나면 Backend를 다시 확인
+// 헬스체크에 성공하면 active로 복귀하고, 실패하면 BACKEND_DOWN_COOLDOWN 동안 down
+func (h *ProxyHandler) finishDrain(b *backend) {
+	deadline := time.Now().Add(time.Duration(h.config.BackendDrainTimeout) * time.Second)
+	for b.inflight.Load() > 0 && time.Now().Before(deadline) {
+		time.Sleep(drainPollInterval)
+	}
+	if remaining := b.inflight.Load(); remaining > 0 {
+		log.Printf("⚠️ Backend 드레이닝 시간 초과: %s (진행 중 %d)", b.url.Host, remaining)
+	}
+
+	err := h.probeTarget(context.Background(), b.url)
+	if err != nil {
+		b.markDown(time.Duration(h.config.BackendDownCooldown) * time.Second)
+	}
+	b.draining.Store(false)
+
+	if err != nil {
+		log.Printf("🚰 Backend 드레이닝 완료, 헬스체크 실패: %s (%v)", b.url.Host, err)
+		return
+	}
+	log.Printf("🚰 Backend 드레이닝 완료, 복귀: %s", b.url.Host)
+}
+
+// knownBackends는 등록된 모든 Backend (기본, BACKEND_URLS, BACKEND_TARGETS, 쿼리 분류) 반환
+func (h *ProxyHandler) knownBackends() []*backend {
+	seen := map[string]bool{}
+	var backends []*backend
+	add := func(b *backend) {
+		if key := backendKey(b.url); !seen[key] {
+			seen[key] = true
+			backends = append(backends, b)
+		}
+	}
+
+	add(h.backend)
+	if h.pool != nil {
+		for _, m := range h.pool.members {
+			add(m.backend)
+		}
+	}
+	for _, b := range h.targets {
+		add(b)
+	}
+	if h.queryRouter != nil {
+		for _, route := range h.queryRouter.routes {
+			add(route.backend)
+		}
+	}
+	return backends
+}
+
+// backendStates는 /health용 Backend별 상태 (active / draining / down)와 진행 중인 요청 수
+func (h *ProxyHandler) backendStates() []map[string]any {
+	now := time.Now()
+	backends := h.knownBackends()
+	states := make([]map[string]any, 0, len(backends))
+	for _, b := range backends {
+		states = append(states, map[string]any{
+			"url":      b.url.String(),
+			"state":    b.state(now),
+			"inflight": b.inflight.Load(),
+		})
+	}
+	return states
+}
+
+// handleBackendDrain은 관리자가 지정한 Backend를 드레이닝 (POST /admin/backends/drain?target=URL, 관리자 전용)
+func (h *ProxyHandler) handleBackendDrain(w http.ResponseWriter, r *http.Request) {
+	if !h.requireAdmin(w, r) {
+		return
+	}
+
+	target, err := url.Parse(r.URL.Query().Get("target"))
+	if err != nil || target.Host == "" {
+		middleware.WriteError(w, r, http.StatusBadRequest, "target 파라미터에 Backend URL이 필요합니다.")
+		return
+	}
+
+	for _, b := range h.knownBackends() {
+		if backendKey(b.url) != backendKey(target) {
+			continue
+		}
+		started := h.drain(b, "관리자 요청")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(map[string]any{
+			"url":      b.url.String(),
+			"state":    b.state(time.Now()),
+			"inflight": b.inflight.Load(),
+			"started":  started,
+		})
+		return
+	}
+
+	middleware.WriteError(w, r, http.StatusNotFound, "등록되지 않은 Backend입니다.")
+}
+
+// drainStarted는 Gateway 드레이닝 시작 시각 반환 (드레이닝 중이 아니면 false)
+func (h *ProxyHandler) drainStarted() (time.Time, bool) {
+	since := h.drainingSince.Load()
+	if since == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(0, since), true
+}
+
+// handleGatewayDrain은 무중단 배포를 위해 Gateway 자체를 드레이닝 (POST /admin/drain, 관리자 전용)
+// readiness만 503으로 바꿔 로드밸런서가 새 트래픽을 보내지 않게 하고, 서버는 계속 요청을 처리하므로
+// 진행 중인 요청이 끝난 뒤 오케스트레이터가 SIGTERM을 보내면 된다. 되돌리려면 프로세스를 재시작
+func (h *ProxyHandler) handleGatewayDrain(w http.ResponseWriter, r *http.Request) {
+	if !h.requireAdmin(w, r) {
+		return
+	}
+
+	started := h.drainingSince.CompareAndSwap(0, time.Now().UnixNano())
+	if started {
+		log.Println("🚧 Gateway 드레이닝 시작: readiness 503 전환 (관리자 요청)")
+	}
+	since, _ := h.drainStarted()
+
+	var inflight int64
+	for _, b := range h.knownBackends() {
+		inflight += b.inflight.Load()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]any{
+		"draining":       true,
+		"draining_since": since.Format(time.RFC3339),
+		"started":        started,
+		"inflight":       inflight,
+		"active_streams": h.activeStreams.Load(),
+	})
+}
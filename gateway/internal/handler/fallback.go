@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/devbrain/gateway/internal/cache"
+	"github.com/devbrain/gateway/internal/middleware"
+)
+
+// contextKey는 요청 컨텍스트에 값을 저장하기 위한 키 타입
+type contextKey int
+
+const (
+	// queryContextKey는 프록시 에러 핸들러에서 쿼리를 조회하기 위한 키
+	queryContextKey contextKey = iota
+	// backendContextKey는 쿼리 분류로 지정된 Backend를 전달하기 위한 키
+	backendContextKey
+	// backendStartContextKey는 적응형 Rate Limiting용 Backend 지연 시간 측정 시작 시각 키
+	backendStartContextKey
+	// clientCacheKeyContextKey는 클라이언트가 X-Cache-Key로 지정한 캐시 키
+	clientCacheKeyContextKey
+	// cacheDisabledContextKey는 CACHE_DISABLED_PATHS에 해당해 캐시를 거치지 않는 요청 표시
+	cacheDisabledContextKey
+	// originalQueryContextKey는 QUERY_REDACT로 마스킹하기 전 원본 쿼리 (QUERY_REDACT_FORWARD=original)
+	originalQueryContextKey
+	// cacheTenantContextKey는 CACHE_TENANT로 결정한 캐시 테넌트 식별자
+	cacheTenantContextKey
+)
+
+// withQuery는 요청 컨텍스트에 채팅 쿼리를 저장
+func withQuery(r *http.Request, query string) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), queryContextKey, query))
+}
+
+// queryFromContext는 요청 컨텍스트에 저장된 채팅 쿼리 반환
+func queryFromContext(ctx context.Context) string {
+	query, _ := ctx.Value(queryContextKey).(string)
+	return query
+}
+
+// handleProxyError는 리버스 프록시 에러 핸들러
+// 채팅 요청이면 stale 캐시로 응답을 시도하고, 없으면 fallback 메시지 반환
+func (h *ProxyHandler) handleProxyError(w http.ResponseWriter, r *http.Request, err error) {
+	log.Printf("❌ 프록시 에러: %v", err)
+
+	if query := queryFromContext(r.Context()); query != "" {
+		if cached := h.getStale(r.Context(), query); cached != nil {
+			log.Printf("🧊 Stale 캐시 응답: %s", query[:min(30, len(query))])
+			w.Header().Set("Content-Type", "application/json")
+			h.setCacheHit(w, r, "STALE", cached)
+			json.NewEncoder(w).Encode(map[string]any{
+				"query":    query,
+				"response": cached.Response,
+				"cached":   true,
+				"stale":    true,
+			})
+			return
+		}
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		middleware.WriteError(w, r, http.StatusGatewayTimeout, "Backend did not respond in time")
+		return
+	}
+	h.writeBackendUnavailable(w, r)
+}
+
+// getStale는 Backend 장애 시 사용할 캐시 항목 조회 (만료 후 보관 기간 포함)
+func (h *ProxyHandler) getStale(ctx context.Context, query string) *cache.CachedResponse {
+	staler, ok := h.cache.(cache.StaleReader)
+	if !ok || !h.cacheEnabled(ctx) || !h.cache.IsConnected() {
+		return nil
+	}
+
+	cached, err := staler.GetStale(cacheQuery(ctx, query))
+	if err != nil || cached == nil || cached.Negative {
+		return nil
+	}
+	return cached
+}
+
+// writeBackendUnavailable는 설정된 fallback 메시지로 502 응답
+func (h *ProxyHandler) writeBackendUnavailable(w http.ResponseWriter, r *http.Request) {
+	middleware.WriteErrorDetails(w, r, http.StatusBadGateway, "Backend Unavailable", h.config.FallbackMessage, nil)
+}
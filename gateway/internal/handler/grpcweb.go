@@ -0,0 +1,220 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/devbrain/gateway/internal/middleware"
+	"golang.org/x/net/http2"
+)
+
+// grpc-web 프레임 플래그 (트레일러 프레임)
+const grpcWebTrailerFlag = 0x80
+
+// grpcStatusUnavailable은 Backend 연결 실패 시 반환하는 gRPC 상태 코드 (UNAVAILABLE)
+const grpcStatusUnavailable = 14
+
+// grpcWebBridge는 grpc-web 요청을 HTTP/2 gRPC 호출로 변환하는 브릿지
+// 기존 리버스 프록시 경로와 완전히 분리되어 GRPC_PATH_PREFIX 요청만 처리한다.
+type grpcWebBridge struct {
+	target *url.URL
+	client *http.Client
+}
+
+// newGRPCWebBridge는 Backend URL에 맞는 HTTP/2 클라이언트로 브릿지 생성
+// http:// 대상은 h2c(평문 HTTP/2), https:// 대상은 TLS 위 HTTP/2 사용 (tlsConfig가 있으면 mTLS 설정 적용)
+func newGRPCWebBridge(target *url.URL, tlsConfig *tls.Config) *grpcWebBridge {
+	transport := &http2.Transport{TLSClientConfig: tlsConfig}
+	if target.Scheme == "http" {
+		transport.AllowHTTP = true
+		transport.DialTLSContext = func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, addr)
+		}
+	}
+
+	return &grpcWebBridge{
+		target: target,
+		client: &http.Client{Transport: transport},
+	}
+}
+
+// isGRPCWebRequest는 grpc-web 요청인지 Content-Type으로 확인
+func isGRPCWebRequest(r *http.Request) bool {
+	return strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc-web")
+}
+
+// isGRPCPath는 경로가 GRPC_PATH_PREFIX 아래인지 확인
+// 접두사 뒤가 경로 구분자여야 하므로 "/api"를 설정해도 "/apidocs" 같은 경로는 포함되지 않는다.
+func (h *ProxyHandler) isGRPCPath(path string) bool {
+	prefix := h.config.GRPCPathPrefix
+	if h.grpcBridge == nil || !strings.HasPrefix(path, prefix) {
+		return false
+	}
+	return strings.HasSuffix(prefix, "/") || len(path) == len(prefix) || path[len(prefix)] == '/'
+}
+
+// handleGRPCWeb은 grpc-web 요청을 gRPC로 변환해 Backend를 호출하고 응답을 grpc-web으로 되돌림
+func (h *ProxyHandler) handleGRPCWeb(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || !isGRPCWebRequest(r) {
+		middleware.WriteErrorDetails(w, r, http.StatusUnsupportedMediaType, "grpc-web request required", "", nil)
+		return
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	textMode := strings.HasPrefix(contentType, "application/grpc-web-text")
+
+	// 요청 바디: grpc-web 바이너리 프레임은 gRPC 프레임과 동일, text 모드는 base64 디코딩
+	var body io.Reader = r.Body
+	if textMode {
+		body = base64.NewDecoder(base64.StdEncoding, r.Body)
+	}
+
+	backendURL := *h.grpcBridge.target
+	backendURL.Path = r.URL.Path
+	backendURL.RawQuery = r.URL.RawQuery
+
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodPost, backendURL.String(), body)
+	if err != nil {
+		h.writeGRPCWebError(w, contentType, grpcStatusUnavailable, err.Error())
+		return
+	}
+
+	// gRPC 메타데이터 전달 (grpc-web 전용/홉 헤더 제외)
+	for name, values := range r.Header {
+		switch strings.ToLower(name) {
+		case "content-type", "content-length", "accept", "accept-encoding", "connection",
+			"x-grpc-web", "x-user-agent", "origin", "referer", "host":
+			continue
+		}
+		req.Header[name] = values
+	}
+	req.Header.Set("Content-Type", grpcContentType(contentType))
+	req.Header.Set("TE", "trailers")
+	h.rewriteBackendRequest(req, h.clientIP(r))
+
+	// 다른 Backend 호출과 같은 동시 요청 한도 적용 (BACKEND_MAX_CONCURRENCY, 스트림이 끝날 때까지 유지)
+	release, err := h.backendLimiter.acquire(r.Context())
+	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			log.Printf("⚠️ Backend 동시 요청 한도 초과 (gRPC): %v", err)
+			h.writeGRPCWebError(w, contentType, grpcStatusUnavailable, "backend busy")
+		}
+		return
+	}
+	defer release()
+
+	resp, err := h.grpcBridge.client.Do(req)
+	if err != nil {
+		log.Printf("❌ gRPC Backend 연결 실패: %v", err)
+		h.writeGRPCWebError(w, contentType, grpcStatusUnavailable, "backend unavailable")
+		return
+	}
+	defer resp.Body.Close()
+
+	// 응답 헤더 (gRPC 메타데이터) 복사
+	for name, values := range resp.Header {
+		if strings.EqualFold(name, "Content-Type") {
+			continue
+		}
+		w.Header()[name] = values
+	}
+	w.Header().Set("Content-Type", grpcWebContentType(contentType))
+	w.WriteHeader(http.StatusOK)
+
+	out := newGRPCWebWriter(w, textMode)
+	if _, err := io.Copy(out, resp.Body); err != nil {
+		log.Printf("⚠️ gRPC 응답 전달 실패: %v", err)
+		return
+	}
+
+	// gRPC 트레일러를 grpc-web 트레일러 프레임으로 변환
+	// (trailers-only 응답이면 grpc-status가 헤더에 들어 있음)
+	trailer := resp.Trailer.Clone()
+	if trailer == nil {
+		trailer = http.Header{}
+	}
+	for _, name := range []string{"Grpc-Status", "Grpc-Message"} {
+		if trailer.Get(name) == "" && resp.Header.Get(name) != "" {
+			trailer.Set(name, resp.Header.Get(name))
+		}
+	}
+	out.Write(encodeGRPCWebTrailer(trailer))
+}
+
+// writeGRPCWebError는 trailers-only 형식의 grpc-web 에러 응답 작성
+func (h *ProxyHandler) writeGRPCWebError(w http.ResponseWriter, contentType string, code int, message string) {
+	w.Header().Set("Content-Type", grpcWebContentType(contentType))
+	w.Header().Set("Grpc-Status", fmt.Sprint(code))
+	w.Header().Set("Grpc-Message", message)
+	w.WriteHeader(http.StatusOK)
+}
+
+// grpcContentType은 grpc-web Content-Type을 대응하는 gRPC Content-Type으로 변환
+func grpcContentType(grpcWebType string) string {
+	if _, suffix, ok := strings.Cut(grpcWebType, "+"); ok {
+		return "application/grpc+" + suffix
+	}
+	return "application/grpc"
+}
+
+// grpcWebContentType은 응답에 사용할 grpc-web Content-Type 반환 (요청과 동일 형식)
+func grpcWebContentType(requestType string) string {
+	if mediaType, _, _ := strings.Cut(requestType, ";"); mediaType != "" {
+		return strings.TrimSpace(mediaType)
+	}
+	return "application/grpc-web+proto"
+}
+
+// encodeGRPCWebTrailer는 트레일러를 grpc-web 트레일러 프레임으로 인코딩
+func encodeGRPCWebTrailer(trailer http.Header) []byte {
+	var payload bytes.Buffer
+	for name, values := range trailer {
+		for _, value := range values {
+			fmt.Fprintf(&payload, "%s: %s\r\n", strings.ToLower(name), value)
+		}
+	}
+
+	frame := make([]byte, 5, 5+payload.Len())
+	frame[0] = grpcWebTrailerFlag
+	binary.BigEndian.PutUint32(frame[1:], uint32(payload.Len()))
+	return append(frame, payload.Bytes()...)
+}
+
+// grpcWebWriter는 gRPC 응답 프레임을 grpc-web 클라이언트로 전달 (text 모드는 base64 인코딩)
+type grpcWebWriter struct {
+	w        http.ResponseWriter
+	textMode bool
+}
+
+func newGRPCWebWriter(w http.ResponseWriter, textMode bool) *grpcWebWriter {
+	return &grpcWebWriter{w: w, textMode: textMode}
+}
+
+// Write는 청크를 기록하고 즉시 Flush (서버 스트리밍 RPC 지원)
+// text 모드에서는 청크마다 패딩된 base64 세그먼트로 인코딩
+func (gw *grpcWebWriter) Write(p []byte) (int, error) {
+	data := p
+	if gw.textMode {
+		data = []byte(base64.StdEncoding.EncodeToString(p))
+	}
+
+	if _, err := gw.w.Write(data); err != nil {
+		return 0, err
+	}
+	if flusher, ok := gw.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return len(p), nil
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// handleChatHead는 채팅 엔드포인트의 HEAD 요청 처리
+// 캐시 히트면 GET과 같은 헤더(X-Cache, Content-Length 등)를 바디 없이 반환하고,
+// 미스면 Backend 생성을 트리거하지 않고 X-Cache: MISS만 반환
+func (h *ProxyHandler) handleChatHead(w http.ResponseWriter, r *http.Request) {
+	r, query := h.redactQuery(r, r.URL.Query().Get(h.config.QueryParamName))
+	stream := r.URL.Path == "/api/chat/stream"
+
+	r, ok := h.withClientCacheKey(w, r)
+	if !ok {
+		return
+	}
+
+	if query != "" {
+		if cached, score := h.getCached(r.Context(), query); cached != nil {
+			h.setCacheKeyHeader(w, r, query)
+			setSimilarityHeader(w, score)
+			if stream {
+				// SSE 응답은 길이를 미리 알 수 없음
+				h.writeHeadHit(w, r, "text/event-stream", -1)
+				return
+			}
+			w.Header().Set("Age", strconv.FormatInt(cacheAge(cached), 10))
+			h.writeHeadHit(w, r, "application/json", len(h.cachedSyncBody(query, cached)))
+			return
+		}
+	}
+
+	h.writeHeadMiss(w, r)
+}
+
+// writeHeadHit은 캐시 히트 HEAD 응답 작성 (contentLength < 0이면 생략)
+func (h *ProxyHandler) writeHeadHit(w http.ResponseWriter, r *http.Request, contentType string, contentLength int) {
+	if contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
+	if contentLength >= 0 {
+		w.Header().Set("Content-Length", strconv.Itoa(contentLength))
+	}
+	setCacheStatus(w, r, "HIT")
+	w.WriteHeader(http.StatusOK)
+}
+
+// writeHeadMiss는 캐시 미스 HEAD 응답 작성
+// Cache-Control: only-if-cached 요청이면 404, 아니면 200 + X-Cache: MISS
+func (h *ProxyHandler) writeHeadMiss(w http.ResponseWriter, r *http.Request) {
+	setCacheStatus(w, r, "MISS")
+	if strings.Contains(strings.ToLower(r.Header.Get("Cache-Control")), "only-if-cached") {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
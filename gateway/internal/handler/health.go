@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/devbrain/gateway/internal/cache"
+	"github.com/devbrain/gateway/internal/version"
+)
+
+// backendProbeTimeout는 readiness 체크 시 Backend 응답 대기 시간
+const backendProbeTimeout = 2 * time.Second
+
+// handleLiveness는 프로세스 생존 여부만 확인 (항상 200)
+func (h *ProxyHandler) handleLiveness(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"status":  "ok",
+		"service": "devbrain-gateway",
+	})
+}
+
+// handleVersion은 빌드 정보 반환 (Backend 상태와 무관하게 항상 200)
+func (h *ProxyHandler) handleVersion(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(version.Info())
+}
+
+// handleReadiness는 트래픽 처리 가능 여부 확인
+// Backend에 연결할 수 있고, CACHE_REQUIRED인 경우 Redis도 연결되어 있어야 200
+// Gateway가 드레이닝 중이면 (POST /admin/drain) Backend 확인 없이 503
+func (h *ProxyHandler) handleReadiness(w http.ResponseWriter, r *http.Request) {
+	if since, ok := h.drainStarted(); ok {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]any{
+			"status":         "draining",
+			"service":        "devbrain-gateway",
+			"draining_since": since.Format(time.RFC3339),
+		})
+		return
+	}
+
+	redisUp := h.cache.IsConnected()
+	backendUp := h.cachedProbe(r.Context()) == nil
+
+	ready := backendUp && (redisUp || !h.config.CacheRequired)
+
+	status := map[string]any{
+		"status":   "ok",
+		"service":  "devbrain-gateway",
+		"redis":    redisUp,
+		"backend":  backendUp,
+		"backends": h.backendStates(),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if !ready {
+		status["status"] = "unavailable"
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(status)
+}
+
+// probeBackend는 기본 Backend 헬스체크 엔드포인트를 호출하고 실패 시 에러 반환
+func (h *ProxyHandler) probeBackend(ctx context.Context) error {
+	return h.probeTarget(ctx, h.backend.url)
+}
+
+// probeTarget은 지정한 Backend의 헬스체크 엔드포인트를 호출하고 실패 시 에러 반환
+func (h *ProxyHandler) probeTarget(ctx context.Context, target *url.URL) error {
+	ctx, cancel := context.WithTimeout(ctx, backendProbeTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String()+h.config.BackendHealthPath, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if !h.healthyStatus(resp.StatusCode) {
+		return fmt.Errorf("unexpected backend health status: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// healthyStatus는 Backend 헬스체크 응답 코드가 정상인지 확인
+// BACKEND_HEALTH_STATUS가 비어 있으면 2xx를 정상으로 취급
+func (h *ProxyHandler) healthyStatus(code int) bool {
+	if len(h.config.BackendHealthStatus) == 0 {
+		return code >= 200 && code < 300
+	}
+	for _, expected := range h.config.BackendHealthStatus {
+		if code == expected {
+			return true
+		}
+	}
+	return false
+}
+
+// probeResult는 최근 Backend 헬스체크 결과 (readiness 폴링마다 Backend를 호출하지 않도록 보관)
+type probeResult struct {
+	mu        sync.Mutex
+	checkedAt time.Time
+	err       error
+}
+
+// cachedProbe는 BACKEND_HEALTH_CACHE 기간 내의 헬스체크 결과를 재사용
+func (h *ProxyHandler) cachedProbe(ctx context.Context) error {
+	ttl := time.Duration(h.config.BackendHealthCache) * time.Second
+	if ttl <= 0 {
+		return h.probeBackend(ctx)
+	}
+
+	h.lastProbe.mu.Lock()
+	defer h.lastProbe.mu.Unlock()
+
+	if !h.lastProbe.checkedAt.IsZero() && time.Since(h.lastProbe.checkedAt) < ttl {
+		return h.lastProbe.err
+	}
+	h.lastProbe.err = h.probeBackend(ctx)
+	h.lastProbe.checkedAt = time.Now()
+	return h.lastProbe.err
+}
+
+// handleCacheStats는 캐시 통계 (항목 수, Redis 명령별 지연 시간/에러) 반환
+func (h *ProxyHandler) handleCacheStats(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	stats, err := h.cache.GetStats()
+	if err != nil {
+		report := map[string]any{"error": err.Error()}
+		if diag, ok := h.cache.(cache.Diagnoser); ok {
+			report["operations"] = diag.Metrics()
+		}
+		// 재연결 중이면 끊김/재연결 횟수도 함께 표시
+		if conn, ok := h.cache.(cache.ConnectionStatter); ok {
+			report["connection"] = conn.ConnectionStats()
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(report)
+		return
+	}
+	stats["hits"] = h.hits.snapshot()
+	if h.config.CacheRefreshProbability > 0 {
+		stats["freshness"] = h.freshness.snapshot()
+	}
+	if versioner, ok := h.cache.(cache.KeyVersioner); ok {
+		stats["key_version"] = versioner.KeyVersion()
+	}
+	json.NewEncoder(w).Encode(stats)
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// apiHealthOnlyBackend는 /api/health만 제공하는 Backend (이 저장소의 ChatController와 같은 경로)
+var apiHealthOnlyBackend = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/api/health" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Write([]byte("OK"))
+})
+
+func TestReadinessWithDefaultBackendHealthPath(t *testing.T) {
+	h := newTestHandler(t, apiHealthOnlyBackend)
+
+	for _, path := range []string{"/healthz/ready", "/health", "/api/health"} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s: status %d, want 200: %s", path, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestReadinessWithUnservedBackendHealthPath(t *testing.T) {
+	t.Setenv("BACKEND_HEALTH_PATH", "/health")
+	h := newTestHandler(t, apiHealthOnlyBackend)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz/ready", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status %d, want 503 when the backend does not serve BACKEND_HEALTH_PATH", rec.Code)
+	}
+}
+
+func TestFinishDrainRestoresHealthyBackend(t *testing.T) {
+	h := newTestHandler(t, apiHealthOnlyBackend)
+
+	h.backend.draining.Store(true)
+	h.finishDrain(h.backend)
+	if h.backend.draining.Load() || !h.backend.available(time.Now()) {
+		t.Errorf("backend state %q after drain, want active", h.backend.state(time.Now()))
+	}
+}
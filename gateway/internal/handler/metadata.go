@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/devbrain/gateway/internal/version"
+)
+
+// metadataField는 INJECT_METADATA 설정 시 동기 채팅 응답 JSON에 추가하는 필드 이름
+const metadataField = "gateway"
+
+// writeWithMetadata는 버퍼링된 동기 채팅 응답에 gateway 메타데이터를 합쳐서 전송
+// (Gateway 버전, 캐시 상태, 처리 시간). JSON 객체가 아닌 응답은 그대로 전달
+func (h *ProxyHandler) writeWithMetadata(w http.ResponseWriter, buf *bufferedResponse, start time.Time) {
+	if buf.header.Get("Content-Encoding") != "" || !isJSONContentType(buf.header.Get("Content-Type")) {
+		buf.writeTo(w, nil)
+		return
+	}
+
+	body, ok := injectMetadata(buf.body.Bytes(), map[string]any{
+		"version":       version.Version,
+		"cache":         buf.header.Get("X-Cache"),
+		"processing_ms": time.Since(start).Milliseconds(),
+	})
+	if !ok {
+		buf.writeTo(w, nil)
+		return
+	}
+	buf.writeTo(w, body)
+}
+
+// injectMetadata는 JSON 객체 바디에 gateway 객체를 병합 (기존 필드는 덮어쓰지 않음)
+// 바디가 JSON 객체가 아니거나 gateway 필드가 객체가 아니면 false
+func injectMetadata(body []byte, metadata map[string]any) ([]byte, bool) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil || fields == nil {
+		return nil, false
+	}
+
+	gateway := make(map[string]json.RawMessage)
+	if existing, ok := fields[metadataField]; ok {
+		if err := json.Unmarshal(existing, &gateway); err != nil || gateway == nil {
+			return nil, false
+		}
+	}
+	for k, v := range metadata {
+		if _, ok := gateway[k]; ok {
+			continue
+		}
+		gateway[k], _ = json.Marshal(v)
+	}
+
+	fields[metadataField], _ = json.Marshal(gateway)
+	result, err := json.Marshal(fields)
+	if err != nil {
+		return nil, false
+	}
+	return append(result, '\n'), true
+}
+
+// isJSONContentType은 Content-Type이 JSON인지 확인 (application/json, application/*+json)
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/devbrain/gateway/internal/cache"
+)
+
+// hitCounter는 포지티브/네거티브 캐시 히트 수 (/api/cache/stats)
+type hitCounter struct {
+	positive atomic.Int64
+	negative atomic.Int64
+}
+
+// snapshot은 통계 응답용 히트 수
+func (c *hitCounter) snapshot() map[string]int64 {
+	return map[string]int64{
+		"positive": c.positive.Load(),
+		"negative": c.negative.Load(),
+	}
+}
+
+// recordHit은 캐시 히트를 종류별로 집계하고 네거티브 항목이면 X-Cache-Negative 헤더 설정
+func (h *ProxyHandler) recordHit(w http.ResponseWriter, cached *cache.CachedResponse) {
+	if !cached.Negative {
+		h.hits.positive.Add(1)
+		return
+	}
+	h.hits.negative.Add(1)
+	w.Header().Set("X-Cache-Negative", "true")
+}
+
+// cacheNegative는 답이 없는 질문(404 또는 빈 답변)을 NEGATIVE_CACHE_TTL 동안 캐시
+// 같은 질문이 반복될 때 Backend를 매번 호출하지 않도록 짧게 보관하며, 시맨틱 캐시에는 저장하지 않음
+func (h *ProxyHandler) cacheNegative(ctx context.Context, query string, status int, body string) {
+	short := query[:min(30, len(query))]
+	writer, ok := h.cache.(cache.NegativeWriter)
+	if !ok || !h.cache.IsConnected() {
+		return
+	}
+
+	ttl := time.Duration(h.config.NegativeCacheTTL) * time.Second
+	if err := writer.SetNegative(cacheQuery(ctx, query), status, body, ttl); err != nil {
+		log.Printf("⚠️ 네거티브 캐시 저장 실패: %v", err)
+		return
+	}
+	log.Printf("🚫 네거티브 캐시 저장 (상태 %d, %v): %s", status, ttl, short)
+}
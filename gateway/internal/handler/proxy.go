@@ -1,61 +1,246 @@
 package handler
 
 import (
-	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
-	"net/http/httputil"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/devbrain/gateway/internal/cache"
 	"github.com/devbrain/gateway/internal/config"
+	"github.com/devbrain/gateway/internal/middleware"
 )
 
 // ProxyHandler는 Backend로 요청을 프록시하는 핸들러
 type ProxyHandler struct {
-	backendURL  *url.URL
-	proxy       *httputil.ReverseProxy
-	redisClient *cache.RedisClient
-	config      *config.Config
+	backend     *backend            // 기본 Backend (헬스체크, grpc-web 기본 대상)
+	pool        *backendPool        // 가중치 부하 분산 (BACKEND_URLS 설정 시)
+	queryRouter *queryRouter        // 쿼리 분류별 Backend (QUERY_ROUTES_FILE 설정 시)
+	rootPage    *rootPage           // "/" 정적 페이지 (ROOT_PAGE 설정 시, 없으면 404)
+	targets     map[string]*backend // X-Backend-Target으로 선택 가능한 Backend (허용 목록)
+	doneMarkers doneMarkers         // SSE 스트림 완료 표시
+	grpcBridge  *grpcWebBridge      // grpc-web → gRPC 브릿지 (GRPC_PATH_PREFIX 설정 시)
+	transport   *http.Transport     // Backend 연결 공유 Transport (BACKEND_TLS_* mTLS 설정 포함)
+	redactor    *queryRedactor      // 채팅 쿼리 개인정보 마스킹 (QUERY_REDACT 설정 시)
+	client      *http.Client        // 리버스 프록시 외 Backend 직접 호출용 (transport 사용)
+
+	// Backend 채팅 요청용 (리다이렉트는 FOLLOW_REDIRECTS일 때 같은 호스트로만 따라감, checkRedirect)
+	backendClient *http.Client
+
+	queryParamAllowlist map[string]bool // 일반 프록시로 전달할 쿼리 파라미터 (비어 있으면 전체)
+	headerAllowlist     map[string]bool // Backend로 전달할 요청 헤더 (nil이면 전체, FORWARD_HEADER_ALLOWLIST)
+	cacheableStatus     map[int]bool    // 동기 채팅 응답을 캐시할 상태 코드 (CACHEABLE_STATUS)
+	flight              flightGroup     // 동기 채팅 Backend 호출 합치기 (캐시 키 단위)
+	backendLimiter      *backendLimiter
+	cache               cache.Cache          // 응답 캐시 (Redis 또는 인메모리)
+	semantic            *cache.SemanticCache // 시맨틱 캐시 (임베딩 설정 시)
+	config              *config.Config
+	clientIPs           *middleware.ClientIPResolver // Backend로 전달할 클라이언트 IP 결정 (TRUSTED_PROXIES 반영)
+
+	// 진단용 상태
+	rateLimiter   LimiterCounter
+	latency       LatencyReporter // 요청 처리 시간 히스토그램 (METRICS_ENABLED 설정 시)
+	activeStreams atomic.Int64
+	streamStalls  atomic.Int64    // 느린 클라이언트로 스트림 버퍼가 가득 찬 누적 횟수 (SSE_BUFFER_SIZE)
+	sessions      streamSessions  // 세션별 진행 중인 스트림 (BACKEND_ABORT_PATH)
+	drainingSince atomic.Int64    // Gateway 드레이닝 시작 시각 (UnixNano, 0이면 정상 운영)
+	lastProbe     probeResult     // readiness용 Backend 헬스체크 결과
+	hits          hitCounter      // 포지티브/네거티브 캐시 히트 수
+	freshness     freshnessProbes // 캐시 신선도 검사 (CACHE_REFRESH_PROBABILITY)
+
+	// 적응형 Rate Limiting용 Backend 상태 (ADAPTIVE_RATE_LIMIT 설정 시, 없으면 nil)
+	health *backendHealth
+}
+
+// LimiterCounter는 진단 리포트와 /admin/ratelimit에 사용할 Rate Limiter 정보 제공자
+type LimiterCounter interface {
+	Len() int
+	NearLimitWarnings() int64
+	Snapshot(limit int) middleware.RateLimitSnapshot
+	ClientState(key string) (middleware.ClientLimitState, bool)
+}
+
+// SetRateLimiter는 진단 리포트에 포함할 Rate Limiter 등록
+func (h *ProxyHandler) SetRateLimiter(rl LimiterCounter) {
+	h.rateLimiter = rl
+}
+
+// LatencyReporter는 /metrics와 진단 리포트에 사용할 요청 처리 시간 히스토그램
+type LatencyReporter interface {
+	Summary() map[string]any
+	WritePrometheus(w io.Writer)
+}
+
+// SetLatencyMetrics는 요청 처리 시간 히스토그램 등록 (등록하면 GET /metrics 활성화)
+func (h *ProxyHandler) SetLatencyMetrics(lr LatencyReporter) {
+	h.latency = lr
+}
+
+// SetClientIPResolver는 Backend로 전달할 클라이언트 IP를 결정할 resolver 등록
+// 등록하지 않으면 직접 연결한 주소를 클라이언트 IP로 사용
+func (h *ProxyHandler) SetClientIPResolver(resolver *middleware.ClientIPResolver) {
+	h.clientIPs = resolver
 }
 
 // NewProxyHandler는 새로운 ProxyHandler 생성
-func NewProxyHandler(backendURL string, redisClient *cache.RedisClient, cfg *config.Config) *ProxyHandler {
+func NewProxyHandler(backendURL string, store cache.Cache, cfg *config.Config) *ProxyHandler {
 	target, err := url.Parse(backendURL)
 	if err != nil {
 		log.Fatalf("❌ Backend URL 파싱 실패: %v", err)
 	}
 
-	proxy := httputil.NewSingleHostReverseProxy(target)
+	h := &ProxyHandler{
+		targets:             make(map[string]*backend),
+		doneMarkers:         parseDoneMarkers(cfg.SSEDoneMarkers),
+		queryParamAllowlist: toSet(cfg.QueryParamAllowlist),
+		headerAllowlist:     newHeaderAllowlist(cfg.ForwardHeaderAllowlist),
+		cacheableStatus:     make(map[int]bool),
+		backendLimiter:      newBackendLimiter(cfg.BackendMaxConcurrency, time.Duration(cfg.BackendQueueTimeout)*time.Second),
+		cache:               store,
+		config:              cfg,
+	}
+	tlsConfig, err := newBackendTLSConfig(cfg)
+	if err != nil {
+		log.Fatalf("❌ Backend TLS 설정 실패: %v", err)
+	}
+	if tlsConfig != nil {
+		log.Printf("🔐 Backend TLS 설정 적용 (클라이언트 인증서: %t, CA: %t)", len(tlsConfig.Certificates) > 0, tlsConfig.RootCAs != nil)
+	}
+	h.transport = newBackendTransport(tlsConfig)
+	h.client = &http.Client{Transport: h.transport}
+	h.backendClient = &http.Client{Transport: h.transport, CheckRedirect: h.checkRedirect}
+
+	if cfg.AdaptiveRateLimit || cfg.CacheHealthGate {
+		h.health = newBackendHealth(cfg.AdaptiveErrorThreshold, time.Duration(cfg.AdaptiveLatencyThreshold)*time.Millisecond,
+			cfg.AdaptiveMinFactor, time.Duration(cfg.AdaptiveWindow)*time.Second)
+	}
+	h.backend = h.newBackend(target)
+	if len(cfg.BackendURLs) > 0 {
+		h.pool = h.newBackendPool(cfg.BackendURLs)
+	}
+	for _, status := range cfg.CacheableStatus {
+		h.cacheableStatus[status] = true
+	}
+
+	// 카나리 테스트용 Backend 허용 목록
+	for _, raw := range cfg.BackendTargets {
+		targetURL, err := url.Parse(raw)
+		if err != nil || targetURL.Host == "" {
+			log.Fatalf("❌ BACKEND_TARGETS URL 파싱 실패: %s", raw)
+		}
+		h.targets[backendKey(targetURL)] = h.newBackend(targetURL)
+	}
+
+	// 쿼리 분류 라우팅: 분류마다 다른 Backend가 답하므로 분류를 캐시 키에도 포함
+	if cfg.QueryRoutesFile != "" {
+		if h.queryRouter, err = h.loadQueryRouter(cfg.QueryRoutesFile); err != nil {
+			log.Fatalf("❌ QUERY_ROUTES_FILE 로드 실패: %v", err)
+		}
+		versioner, ok := store.(cache.KeyVersioner)
+		if !ok {
+			log.Fatal("❌ QUERY_ROUTES_FILE은 캐시 키 공간을 지원하는 캐시 구현이 필요합니다")
+		}
+		versioner.SetKeyScope(h.queryRouter.classify)
+	}
+
+	// 채팅 쿼리 개인정보 마스킹 (opt-in)
+	if h.redactor, err = loadQueryRedactor(cfg.QueryRedact, cfg.QueryRedactFile, cfg.QueryRedactForward); err != nil {
+		log.Fatalf("❌ 쿼리 마스킹 규칙 로드 실패: %v", err)
+	}
+	if h.redactor != nil {
+		log.Printf("🕶️ 쿼리 마스킹 규칙 %d개 (Backend 전달: %s)", len(h.redactor.rules), cfg.QueryRedactForward)
+	}
 
-	// 에러 핸들러 커스터마이징
-	proxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
-		log.Printf("❌ 프록시 에러: %v", err)
-		http.Error(w, `{"error": "Backend Unavailable", "message": "백엔드 서버에 연결할 수 없습니다."}`, http.StatusBadGateway)
+	// "/" 정적 안내 페이지 (opt-in)
+	if cfg.RootPage != "" {
+		if h.rootPage, err = loadRootPage(cfg.RootPage); err != nil {
+			log.Fatalf("❌ ROOT_PAGE 로드 실패: %v", err)
+		}
 	}
 
-	return &ProxyHandler{
-		backendURL:  target,
-		proxy:       proxy,
-		redisClient: redisClient,
-		config:      cfg,
+	// grpc-web 브릿지 (opt-in)
+	if cfg.GRPCPathPrefix != "" {
+		grpcTarget := target
+		if cfg.GRPCBackendURL != "" {
+			if grpcTarget, err = url.Parse(cfg.GRPCBackendURL); err != nil {
+				log.Fatalf("❌ GRPC_BACKEND_URL 파싱 실패: %v", err)
+			}
+		}
+		h.grpcBridge = newGRPCWebBridge(grpcTarget, tlsConfig)
+		log.Printf("🔌 grpc-web 브릿지 활성화: %s → %s", cfg.GRPCPathPrefix, grpcTarget)
 	}
+
+	return h
 }
 
 // ServeHTTP는 HTTP 요청 처리
 func (h *ProxyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	normalizeTrailingSlash(r)
+	r = h.withRouteCachePolicy(r)
+	r, ok := h.withCacheTenant(w, r)
+	if !ok {
+		return
+	}
 	path := r.URL.Path
 
+	// 한도 초과 요청 (RATE_LIMIT_CACHE_FALLBACK): 캐시 히트만 응답
+	if middleware.RateLimitedFromContext(r.Context()) {
+		h.serveRateLimited(w, r)
+		return
+	}
+
 	// 라우팅
 	switch {
-	case path == "/health" || path == "/api/health":
-		h.handleHealth(w, r)
+	case path == "/" && h.rootPage != nil && (r.Method == http.MethodGet || r.Method == http.MethodHead):
+		h.handleRoot(w, r)
+
+	case path == "/healthz/live":
+		h.handleLiveness(w, r)
+
+	case path == "/version" && r.Method == http.MethodGet:
+		h.handleVersion(w, r)
+
+	case path == "/healthz/ready" || path == "/health" || path == "/api/health":
+		// /health, /api/health는 하위 호환을 위한 readiness 별칭
+		h.handleReadiness(w, r)
+
+	case path == "/api/cache/stats" && r.Method == http.MethodGet:
+		h.handleCacheStats(w, r)
+
+	case path == "/metrics" && r.Method == http.MethodGet && h.latency != nil:
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		h.latency.WritePrometheus(w)
+
+	case path == "/admin/diagnostics":
+		h.handleDiagnostics(w, r)
+
+	case path == "/admin/ratelimit" && r.Method == http.MethodGet:
+		h.handleRateLimitState(w, r)
+
+	case path == "/admin/backends/drain" && r.Method == http.MethodPost:
+		h.handleBackendDrain(w, r)
+
+	case path == "/admin/drain" && r.Method == http.MethodPost:
+		h.handleGatewayDrain(w, r)
+
+	case path == "/admin/cache/export" && r.Method == http.MethodGet:
+		h.handleCacheExport(w, r)
+
+	case path == "/admin/cache/import" && r.Method == http.MethodPost:
+		h.handleCacheImport(w, r)
+
+	case r.Method == http.MethodHead && (path == "/api/chat" || path == "/api/chat/stream"):
+		// HEAD는 캐시만 확인하고 Backend 생성은 트리거하지 않음
+		h.handleChatHead(w, r)
 
 	case path == "/api/chat/stream":
 		h.handleChatStream(w, r)
@@ -63,199 +248,582 @@ func (h *ProxyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	case path == "/api/chat" && r.Method == http.MethodPost:
 		h.handleChatSync(w, r)
 
+	case path == "/api/chat/batch" && r.Method == http.MethodPost:
+		h.handleChatBatch(w, r)
+
+	case h.isGRPCPath(path):
+		// Gateway 고정 라우트를 가리지 않도록 정확 일치 라우트 다음에 확인
+		h.handleGRPCWeb(w, r)
+
+	case strings.HasPrefix(path, "/api/") && h.isCacheablePath(r):
+		// CACHEABLE_PATHS 대상은 전체 요청 기반 키로 캐싱
+		h.handleCacheable(w, r)
+
 	case strings.HasPrefix(path, "/api/"):
 		// 일반 API 요청은 그대로 프록시
-		h.proxy.ServeHTTP(w, r)
+		h.serveProxy(w, r)
 
 	case strings.HasPrefix(path, "/swagger") || strings.HasPrefix(path, "/api-docs"):
 		// Swagger UI도 프록시
-		h.proxy.ServeHTTP(w, r)
+		h.serveProxy(w, r)
 
 	default:
-		http.NotFound(w, r)
+		middleware.WriteError(w, r, http.StatusNotFound, "")
+	}
+}
+
+// exactRoutes는 정확히 일치해야 하는 Gateway 라우트 목록
+var exactRoutes = map[string]bool{
+	"/healthz/live":         true,
+	"/healthz/ready":        true,
+	"/version":              true,
+	"/health":               true,
+	"/api/health":           true,
+	"/api/cache/stats":      true,
+	"/metrics":              true,
+	"/admin/diagnostics":    true,
+	"/admin/ratelimit":      true,
+	"/admin/backends/drain": true,
+	"/admin/drain":          true,
+	"/admin/cache/export":   true,
+	"/admin/cache/import":   true,
+	"/api/chat":             true,
+	"/api/chat/stream":      true,
+	"/api/chat/batch":       true,
+}
+
+// HasRoute는 경로가 Gateway가 처리(또는 프록시)하는 라우트인지 확인
+// CORS 미들웨어가 존재하지 않는 경로의 preflight에 응답하지 않도록 사용
+func (h *ProxyHandler) HasRoute(path string) bool {
+	if len(path) > 1 && exactRoutes[strings.TrimRight(path, "/")] {
+		return true
 	}
+	switch {
+	case h.isGRPCPath(path):
+		return true
+	case strings.HasPrefix(path, "/api/"):
+		return true
+	case strings.HasPrefix(path, "/swagger") || strings.HasPrefix(path, "/api-docs"):
+		return true
+	}
+	return false
 }
 
-// handleHealth는 헬스체크 엔드포인트
-func (h *ProxyHandler) handleHealth(w http.ResponseWriter, _ *http.Request) {
-	status := map[string]any{
-		"status":  "ok",
-		"service": "devbrain-gateway",
-		"redis":   h.redisClient.IsConnected(),
+// normalizeTrailingSlash는 정확 일치 라우트에 붙은 끝 슬래시 제거
+// "/api/chat/"와 "/api/chat"을 같은 라우트로 처리하며, "/api/" 같은 접두사 라우트는 건드리지 않음
+func normalizeTrailingSlash(r *http.Request) {
+	path := r.URL.Path
+	if len(path) <= 1 || !strings.HasSuffix(path, "/") {
+		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(status)
+	trimmed := strings.TrimRight(path, "/")
+	if exactRoutes[trimmed] {
+		r.URL.Path = trimmed
+		r.URL.RawPath = ""
+	}
 }
 
 // handleChatSync는 동기 채팅 요청 처리 (캐시 적용)
 func (h *ProxyHandler) handleChatSync(w http.ResponseWriter, r *http.Request) {
+	// INJECT_METADATA: 응답을 버퍼링했다가 gateway 메타데이터를 합쳐서 전송
+	if h.config.InjectMetadata {
+		start, buf := time.Now(), newBufferedResponse()
+		defer h.writeWithMetadata(w, buf, start)
+		w = buf
+	}
+
 	// 요청 바디 읽기
-	body, err := io.ReadAll(r.Body)
+	body, err := h.readRequestBody(r)
 	if err != nil {
-		http.Error(w, `{"error": "Bad Request"}`, http.StatusBadRequest)
+		writeBodyError(w, r, err)
 		return
 	}
 	r.Body = io.NopCloser(bytes.NewBuffer(body))
 
-	// 쿼리 추출
-	var req struct {
-		Query string `json:"query"`
+	// 바디 검증 (쿼리 필수, 선택적 파라미터 타입/범위, strict면 알 수 없는 필드 거부)
+	if errs := h.validateChatBody(body); len(errs) > 0 {
+		writeValidationError(w, r, errs)
+		return
 	}
-	if err := json.Unmarshal(body, &req); err != nil || req.Query == "" {
-		// 파싱 실패시 그냥 프록시
-		r.Body = io.NopCloser(bytes.NewBuffer(body))
-		h.proxy.ServeHTTP(w, r)
+	if !h.validateRequestTimeout(w, r) {
 		return
 	}
 
-	// 캐시 확인
-	if h.config.CacheEnabled && h.redisClient.IsConnected() {
-		if cached, err := h.redisClient.Get(req.Query); err == nil && cached != nil {
-			log.Printf("💾 캐시 히트: %s", req.Query[:min(30, len(req.Query))])
-			w.Header().Set("Content-Type", "application/json")
-			w.Header().Set("X-Cache", "HIT")
-			
-			response := map[string]any{
-				"query":    req.Query,
-				"response": cached.Response,
-				"cached":   true,
-			}
-			json.NewEncoder(w).Encode(response)
-			return
-		}
+	// 쿼리 추출 (QUERY_JSON_FIELD), 마스킹 규칙이 있으면 마스킹된 쿼리를 로그/캐시 키에 사용
+	r, query := h.redactQuery(r, h.extractQuery(body))
+	body = h.redactBody(r.Context(), body, query)
+	r = h.routeQuery(r, query)
+	r, ok := h.withClientCacheKey(w, r)
+	if !ok {
+		return
+	}
+
+	// 캐시 확인 (정확 일치 → 시맨틱)
+	if cached, score := h.getCached(r.Context(), query); cached != nil {
+		log.Printf("💾 캐시 히트: %s", query[:min(30, len(query))])
+		h.writeSyncHit(w, r, query, cached, score)
+		h.probeFreshness(r, query, body, cached, score)
+		return
 	}
 
 	// 캐시 미스: Backend로 프록시하고 응답 캡처
-	log.Printf("🔄 캐시 미스: %s", req.Query[:min(30, len(req.Query))])
-	
-	// 응답 캡처를 위한 래퍼
-	rec := &responseRecorder{
-		ResponseWriter: w,
-		body: &bytes.Buffer{},
+	log.Printf("🔄 캐시 미스: %s", query[:min(30, len(query))])
+
+	// 같은 질문의 동시 요청은 Backend 호출 하나로 합침 (캐시 저장도 한 번)
+	buf := h.fetchChat(r, query, body)
+	if buf.hit != nil {
+		h.writeSyncHit(w, r, query, buf.hit, 1.0)
+		return
 	}
 
-	r.Body = io.NopCloser(bytes.NewBuffer(body))
-	h.proxy.ServeHTTP(rec, r)
+	h.setCacheMiss(w, r, h.cacheableStatus[buf.statusCode])
 
-	// 성공 응답이면 캐시에 저장
-	if rec.statusCode == http.StatusOK && h.config.CacheEnabled && h.redisClient.IsConnected() {
-		var resp struct {
-			Response string `json:"response"`
+	// RESPONSE_ENVELOPE이거나 빈 답변을 fallback 메시지로 대체한 경우 히트와 같은 형태로 재구성,
+	// 아니면 Backend 응답 그대로 전달
+	if h.cacheableStatus[buf.statusCode] {
+		if answer, empty, err := h.resolveAnswer(buf.body.Bytes()); err == nil && (h.config.ResponseEnvelope || empty) {
+			buf.writeTo(w, h.missBody(query, answer))
+			return
 		}
-		if err := json.Unmarshal(rec.body.Bytes(), &resp); err == nil && resp.Response != "" {
-			ttl := time.Duration(h.config.CacheTTL) * time.Second
-			if err := h.redisClient.Set(req.Query, resp.Response, ttl); err != nil {
-				log.Printf("⚠️ 캐시 저장 실패: %v", err)
-			} else {
-				log.Printf("💾 캐시 저장: %s", req.Query[:min(30, len(req.Query))])
-			}
+	}
+	buf.writeTo(w, nil)
+}
+
+// writeSyncHit은 동기 채팅 캐시 히트 응답 작성
+func (h *ProxyHandler) writeSyncHit(w http.ResponseWriter, r *http.Request, query string, cached *cache.CachedResponse, score float64) {
+	w.Header().Set("Content-Type", "application/json")
+	h.setCacheHit(w, r, "HIT", cached)
+	h.setCacheKeyHeader(w, r, query)
+	setSimilarityHeader(w, score)
+	w.Header().Set("Age", strconv.FormatInt(cacheAge(cached), 10))
+	h.recordHit(w, cached)
+	w.WriteHeader(cached.StatusCode())
+	w.Write(h.cachedSyncBody(query, cached))
+}
+
+// cacheSyncResponse는 동기 채팅 Backend 응답을 캐시에 저장
+// 캐시가 꺼져 있거나 연결되지 않았으면 요청마다 로그를 남기지 않고 넘어가며,
+// 그 외에 저장하지 않는 경우 그 이유(클라이언트 취소, Backend Cache-Control, Backend 불안정, 상태 코드, JSON 아님, 답변 없음/빈 답변)를 로그로 남김
+func (h *ProxyHandler) cacheSyncResponse(ctx context.Context, query string, statusCode int, header http.Header, body []byte) {
+	if !h.cacheEnabled(ctx) || !h.cache.IsConnected() {
+		return
+	}
+
+	short := query[:min(30, len(query))]
+	ttl, store := h.backendCacheTTL(header)
+	degraded := h.degradedReason()
+
+	switch {
+	case ctx.Err() != nil:
+		// 중단된 Backend 응답(또는 취소로 인한 502)이 캐시되지 않도록 함
+		log.Printf("⏭️ 캐시 저장 안 함 (클라이언트 취소): %s", short)
+		return
+	case !store:
+		log.Printf("⏭️ 캐시 저장 안 함 (Backend Cache-Control): %s", short)
+		return
+	case degraded != "":
+		log.Printf("⏭️ 캐시 저장 안 함 (Backend 불안정: %s): %s", degraded, short)
+		return
+	case statusCode == http.StatusNotFound && h.config.NegativeCacheTTL > 0:
+		h.cacheNegative(ctx, query, statusCode, string(body))
+		return
+	case !h.cacheableStatus[statusCode]:
+		log.Printf("⏭️ 캐시 저장 안 함 (상태 코드 %d): %s", statusCode, short)
+		return
+	}
+
+	answer, empty, err := h.resolveAnswer(body)
+	if err != nil {
+		log.Printf("⏭️ 캐시 저장 안 함 (%v, path=%s): %s", err, h.config.ResponseAnswerPath, short)
+		return
+	}
+	if empty && !h.config.CacheEmptyResponses {
+		if h.config.NegativeCacheTTL > 0 {
+			h.cacheNegative(ctx, query, http.StatusOK, answer)
+			return
 		}
+		log.Printf("⏭️ 캐시 저장 안 함 (빈 답변, fallback 메시지로 응답): %s", short)
+		return
+	}
+
+	if err := h.cache.Set(cacheQuery(ctx, query), answer, ttl); err != nil {
+		log.Printf("⚠️ 캐시 저장 실패: %v", err)
+		return
+	}
+	log.Printf("💾 캐시 저장: %s", short)
+	if !scopedCacheKey(ctx) {
+		h.storeSemantic(query, ttl)
 	}
 }
 
 // handleChatStream는 SSE 스트리밍 채팅 요청 처리
 func (h *ProxyHandler) handleChatStream(w http.ResponseWriter, r *http.Request) {
-	query := r.URL.Query().Get("q")
+	query := r.URL.Query().Get(h.config.QueryParamName)
 	if query == "" {
-		http.Error(w, `{"error": "Missing query parameter 'q'"}`, http.StatusBadRequest)
+		middleware.WriteErrorDetails(w, r, http.StatusBadRequest, fmt.Sprintf("Missing query parameter '%s'", h.config.QueryParamName), "", nil)
+		return
+	}
+	if !h.validateRequestTimeout(w, r) {
+		return
+	}
+	r, query = h.redactQuery(r, query)
+	r = h.routeQuery(r, query)
+	r, ok := h.withClientCacheKey(w, r)
+	if !ok {
 		return
 	}
 
-	// 캐시 확인 (스트리밍에서도 캐시된 응답이 있으면 사용)
-	if h.config.CacheEnabled && h.redisClient.IsConnected() {
-		if cached, err := h.redisClient.Get(query); err == nil && cached != nil {
-			log.Printf("💾 캐시 히트 (SSE): %s", query[:min(30, len(query))])
-			h.sendCachedSSE(w, cached.Response)
-			return
-		}
+	// 캐시 확인 (스트리밍에서도 캐시된 응답이 있으면 사용, 404 네거티브 항목은 스트림으로 재생할 수 없으므로 제외)
+	if cached, score := h.getCached(r.Context(), query); cached != nil && cached.StatusCode() == http.StatusOK {
+		log.Printf("💾 캐시 히트 (SSE): %s", query[:min(30, len(query))])
+		h.setCacheKeyHeader(w, r, query)
+		setSimilarityHeader(w, score)
+		h.setCacheHit(w, r, "HIT", cached)
+		h.recordHit(w, cached)
+		h.sendCachedSSE(w, r, query, cached)
+		h.probeFreshness(r, query, nil, cached, score)
+		return
 	}
 
 	log.Printf("🔄 SSE 스트리밍 시작: %s", query[:min(30, len(query))])
 
-	// Backend SSE 요청
-	backendURL := fmt.Sprintf("%s/api/chat/stream?q=%s", h.backendURL.String(), url.QueryEscape(query))
-	
-	resp, err := http.Get(backendURL)
+	h.activeStreams.Add(1)
+	defer h.activeStreams.Add(-1)
+
+	// 비정상적으로 긴 생성을 막기 위한 최대 스트리밍 시간 (X-Request-Timeout으로 요청별 지정 가능)
+	ctx, cancel, ok := h.backendContext(w, r, time.Duration(h.config.StreamMaxDuration)*time.Second)
+	if !ok {
+		return
+	}
+	defer cancel()
+
+	// Backend SSE 요청 (스트림이 끝날 때까지 진행 중인 요청으로 집계하여 드레이닝 중에도 마저 전송)
+	b := h.selectBackend(r)
+	untrack := b.track()
+	defer untrack()
+	backendReq, err := http.NewRequestWithContext(ctx, http.MethodGet, h.streamURL(b, h.backendQuery(r.Context(), query)), nil)
+	if err != nil {
+		log.Printf("❌ Backend 요청 생성 실패: %v", err)
+		middleware.WriteError(w, r, http.StatusInternalServerError, "")
+		return
+	}
+	h.copyAllowedHeaders(backendReq.Header, r.Header)
+	h.rewriteBackendRequest(backendReq, h.clientIP(r))
+
+	// Backend가 중단 요청과 스트림을 연결할 수 있도록 세션 ID 전달 (FORWARD_HEADER_ALLOWLIST와 무관)
+	sessionID := h.streamSessionID(r)
+	if sessionID != "" {
+		backendReq.Header.Set(h.config.SessionIDHeader, sessionID)
+	}
+
+	release, err := h.backendLimiter.acquire(r.Context())
+	if err != nil {
+		writeBackendBusy(w, r, err)
+		return
+	}
+	defer release()
+
+	start := time.Now()
+	resp, err := h.backendClient.Do(backendReq)
+	switch {
+	case err == nil:
+		h.health.observe(time.Since(start), resp.StatusCode >= http.StatusInternalServerError)
+	case !errors.Is(err, context.Canceled):
+		h.health.observe(time.Since(start), true)
+		h.drain(b, err.Error())
+	}
 	if err != nil {
 		log.Printf("❌ Backend 연결 실패: %v", err)
-		http.Error(w, `{"error": "Backend Unavailable"}`, http.StatusBadGateway)
+		if cached := h.getStale(r.Context(), query); cached != nil {
+			log.Printf("🧊 Stale 캐시 응답 (SSE): %s", query[:min(30, len(query))])
+			h.setCacheHit(w, r, "STALE", cached)
+			h.sendCachedSSE(w, r, query, cached)
+			return
+		}
+		h.writeBackendUnavailable(w, r)
 		return
 	}
 	defer resp.Body.Close()
 
-	// SSE 헤더 설정
-	w.Header().Set("Content-Type", "text/event-stream")
-	w.Header().Set("Cache-Control", "no-cache")
-	w.Header().Set("Connection", "keep-alive")
-	w.Header().Set("X-Accel-Buffering", "no")
+	// 리다이렉트(인증 페이지 등)는 SSE 스트림이 아니므로 본문을 전달하지 않고 에러 이벤트로 알림
+	if isRedirect(resp.StatusCode) {
+		h.writeStreamRedirect(w, r, resp)
+		return
+	}
 
-	flusher, ok := w.(http.Flusher)
+	// SSE 헤더 설정 (첫 Flush 이전에 캐시 헤더도 함께 설정)
+	h.setCacheMiss(w, r, true)
+	h.setCacheKeyHeader(w, r, query)
+	sw, ok := h.newSSEWriter(w, r)
 	if !ok {
-		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		middleware.WriteError(w, r, http.StatusInternalServerError, "Streaming not supported")
 		return
 	}
+	defer sw.Close()
 
-	// 응답 수집 (캐시용)
-	var fullResponse strings.Builder
-
-	// SSE 이벤트 프록시
-	scanner := bufio.NewScanner(resp.Body)
-	for scanner.Scan() {
-		line := scanner.Text()
-		
-		// 클라이언트로 전달
-		fmt.Fprintln(w, line)
-		flusher.Flush()
+	// SSE 이벤트 프록시 (빈 줄로 끝나는 이벤트 단위로 전달, 캐시용 응답 수집)
+	// 캐시에는 항상 원본 텍스트를 누적 (재생 시 다시 감싸므로 이중 래핑 방지)
+	collector := newSSECollector(h.doneMarkers)
+	if h.config.SSEResultEvent {
+		collector.resultEvent = h.config.SSEResultBackendEvent
+	}
+	events := 0         // 완료 표시 전까지 전달한 이벤트 수 (SSE_MAX_EVENTS)
+	clientGone := false // 클라이언트 쓰기 실패 (연결 종료)
+
+	// forward는 이벤트 하나를 클라이언트로 전달하고, 스트림을 중단해야 하면 false 반환
+	// 쓰기 실패는 클라이언트 연결 종료로 보고 중단하며, 부분 응답은 캐시하지 않음
+	forward := func(ev *sseEvent) bool {
+		if len(ev.lines) > 0 && !collector.done {
+			events++
+			if h.config.SSEMaxEvents > 0 && events > h.config.SSEMaxEvents {
+				log.Printf("✂️ 최대 이벤트 수(%d) 초과, 스트림 종료: %s", h.config.SSEMaxEvents, query[:min(30, len(query))])
+				sw.send("event:truncated\ndata:stream exceeded maximum events\n\n")
+				return false
+			}
+		}
+		// Backend 결과 이벤트는 최종 결과 이벤트에 합쳐서 한 번만 전달
+		if ev.result {
+			return true
+		}
+		// 완료 이벤트 직전에 최종 결과 이벤트 전달 (완료 이벤트에서 연결을 끊는 클라이언트도 받도록)
+		text := h.formatEvent(ev)
+		if ev.final && h.config.SSEResultEvent {
+			text = h.formatResult(query, collector.text.String(), collector.result, map[string]any{
+				"cached": false,
+				"chunks": len(collector.chunks),
+			}) + text
+		}
+		if err := sw.send(text); err != nil {
+			log.Printf("🔌 클라이언트 연결 종료 (SSE): %s", query[:min(30, len(query))])
+			clientGone = true
+			return false
+		}
+		return true
+	}
 
-		// 데이터 라인에서 응답 수집
-		if strings.HasPrefix(line, "data:") {
-			data := strings.TrimPrefix(line, "data:")
-			data = strings.TrimSpace(data)
-			if data != "[DONE]" {
-				fullResponse.WriteString(data)
+	// 클라이언트가 완료 전에 연결을 끊으면 Backend 생성 중단 요청 (BACKEND_ABORT_PATH, 세션의 마지막 스트림인 경우만)
+	if sessionID != "" {
+		untrackSession := h.sessions.track(sessionID)
+		defer func() {
+			if last := untrackSession(); last && !collector.done && (clientGone || r.Context().Err() != nil) {
+				h.abortStream(b, sessionID)
 			}
+		}()
+	}
+
+	// Backend 읽기와 클라이언트 전송 사이의 제한된 버퍼 (느린 클라이언트면 Backend 읽기를 늦춤)
+	lines := newLineReader(resp.Body, h.config.SSEBufferSize)
+	defer func() {
+		lines.close()
+		if stalls := lines.stalls.Load(); stalls > 0 {
+			h.streamStalls.Add(stalls)
+			log.Printf("🐢 느린 클라이언트: 스트림 버퍼가 %d번 가득 참: %s", stalls, query[:min(30, len(query))])
+		}
+	}()
+	for line := range lines.lines {
+		if ev, complete := collector.feed(line); complete && !forward(ev) {
+			return
 		}
 	}
 
-	// 캐시에 저장
-	if h.config.CacheEnabled && h.redisClient.IsConnected() && fullResponse.Len() > 0 {
-		ttl := time.Duration(h.config.CacheTTL) * time.Second
-		if err := h.redisClient.Set(query, fullResponse.String(), ttl); err != nil {
-			log.Printf("⚠️ 캐시 저장 실패: %v", err)
-		} else {
-			log.Printf("💾 캐시 저장 (SSE): %s", query[:min(30, len(query))])
+	// 마지막 빈 줄 없이 정상 종료된 스트림의 남은 이벤트 전달
+	if lines.err == nil && ctx.Err() == nil {
+		if ev, ok := collector.flush(); ok && !forward(ev) {
+			return
 		}
 	}
+
+	// 최대 스트리밍 시간 초과: 타임아웃 이벤트를 보내고 부분 응답은 캐시하지 않음
+	if ctx.Err() == context.DeadlineExceeded {
+		log.Printf("⏱️ 스트리밍 시간 초과: %s", query[:min(30, len(query))])
+		sw.send("event:timeout\ndata:stream exceeded maximum duration\n\n")
+		return
+	}
+
+	// 불완전한 스트림은 캐시하지 않음: 클라이언트 연결 종료(컨텍스트 취소),
+	// Backend 읽기 오류, 또는 Backend가 완료 표시(SSE_DONE_MARKERS)를 보내지 않은 경우
+	if ctx.Err() != nil || lines.err != nil || !collector.done {
+		log.Printf("⚠️ 불완전한 스트림, 캐시 안 함: %s (canceled=%t, done=%t)",
+			query[:min(30, len(query))], ctx.Err() != nil, collector.done)
+		return
+	}
+
+	// 캐시에 저장 (Backend가 완료를 알린 경우만)
+	h.cacheStreamResponse(ctx, query, resp.Header, collector)
 }
 
-// sendCachedSSE는 캐시된 응답을 SSE 형식으로 전송
-func (h *ProxyHandler) sendCachedSSE(w http.ResponseWriter, response string) {
-	w.Header().Set("Content-Type", "text/event-stream")
-	w.Header().Set("Cache-Control", "no-cache")
-	w.Header().Set("Connection", "keep-alive")
-	w.Header().Set("X-Cache", "HIT")
+// cacheStreamResponse는 완료된 Backend 스트림에서 수집한 응답을 캐시에 저장
+// SSE_STORE_CHUNKS면 토큰 이벤트 단위 청크도 함께 저장
+func (h *ProxyHandler) cacheStreamResponse(ctx context.Context, query string, header http.Header, collector *sseCollector) {
+	if !h.cacheEnabled(ctx) || !h.cache.IsConnected() || strings.TrimSpace(collector.text.String()) == "" {
+		return
+	}
 
-	flusher, ok := w.(http.Flusher)
+	ttl, store := h.backendCacheTTL(header)
+	if !store {
+		log.Printf("⏭️ 캐시 저장 안 함 (Backend Cache-Control): %s", query[:min(30, len(query))])
+		return
+	}
+	if degraded := h.degradedReason(); degraded != "" {
+		log.Printf("⏭️ 캐시 저장 안 함 (Backend 불안정: %s): %s", degraded, query[:min(30, len(query))])
+		return
+	}
+
+	var err error
+	if h.config.SSEStoreChunks {
+		err = h.setChunks(cacheQuery(ctx, query), collector.chunks, ttl)
+	} else {
+		err = h.cache.Set(cacheQuery(ctx, query), collector.text.String(), ttl)
+	}
+	if err != nil {
+		log.Printf("⚠️ 캐시 저장 실패: %v", err)
+		return
+	}
+	log.Printf("💾 캐시 저장 (SSE): %s", query[:min(30, len(query))])
+	if !scopedCacheKey(ctx) {
+		h.storeSemantic(query, ttl)
+	}
+}
+
+// setChunks는 SSE 청크 경계를 보존해 저장 (지원하지 않는 캐시 구현이면 이어 붙인 답변으로 저장)
+func (h *ProxyHandler) setChunks(query string, chunks []string, ttl time.Duration) error {
+	if writer, ok := h.cache.(cache.ChunkWriter); ok {
+		return writer.SetChunks(query, chunks, ttl)
+	}
+	return h.cache.Set(query, strings.Join(chunks, ""), ttl)
+}
+
+// extractQuery는 동기 채팅 요청 JSON에서 QUERY_JSON_FIELD 필드의 쿼리 추출
+// 파싱 실패, 필드 없음, 문자열이 아닌 경우 빈 문자열 반환
+func (h *ProxyHandler) extractQuery(body []byte) string {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return ""
+	}
+
+	var query string
+	if err := json.Unmarshal(fields[h.config.QueryJSONField], &query); err != nil {
+		return ""
+	}
+	return query
+}
+
+// cacheAge는 캐시 항목이 저장된 후 경과한 시간 (초)
+func cacheAge(cached *cache.CachedResponse) int64 {
+	if cached.CreatedAt.IsZero() {
+		return 0
+	}
+	return int64(max(time.Since(cached.CreatedAt), 0) / time.Second)
+}
+
+// setCacheStatus는 X-Cache 헤더를 설정하고 접근 로그에 cache 필드 추가, 처리 시간 지표에 캐시 결과 기록
+func setCacheStatus(w http.ResponseWriter, r *http.Request, status string) {
+	w.Header().Set("X-Cache", status)
+	middleware.AddLogField(r.Context(), "cache", status)
+	middleware.SetCacheOutcome(r.Context(), status)
+}
+
+// setCacheHit은 채팅 캐시 응답(HIT / STALE)의 X-Cache 헤더와
+// CACHE_HEADERS 활성화 시 X-Cache-Age(생성 후 경과), X-Cache-TTL(남은 유효 시간) 설정
+func (h *ProxyHandler) setCacheHit(w http.ResponseWriter, r *http.Request, status string, cached *cache.CachedResponse) {
+	setCacheStatus(w, r, status)
+	if !h.config.CacheHeaders {
+		return
+	}
+
+	ttl := int64(0)
+	if !cached.ExpiresAt.IsZero() {
+		ttl = int64(max(time.Until(cached.ExpiresAt), 0) / time.Second)
+	}
+	w.Header().Set("X-Cache-Age", strconv.FormatInt(cacheAge(cached), 10))
+	w.Header().Set("X-Cache-TTL", strconv.FormatInt(ttl, 10))
+}
+
+// setCacheMiss는 채팅 Backend 응답의 X-Cache 헤더 설정
+// 캐시를 사용할 수 없으면 BYPASS, 아니면 MISS이며 CACHE_HEADERS 활성화 시 저장될 항목 기준의
+// X-Cache-Age(0)와 X-Cache-TTL(CACHE_TTL, stored가 false면 생략) 설정
+func (h *ProxyHandler) setCacheMiss(w http.ResponseWriter, r *http.Request, stored bool) {
+	if !h.cacheEnabled(r.Context()) || !h.cache.IsConnected() {
+		setCacheStatus(w, r, "BYPASS")
+		return
+	}
+
+	setCacheStatus(w, r, "MISS")
+	if !h.config.CacheHeaders {
+		return
+	}
+	w.Header().Set("X-Cache-Age", "0")
+	if stored {
+		w.Header().Set("X-Cache-TTL", strconv.Itoa(h.config.CacheTTL))
+	}
+}
+
+// setCacheKeyHeader는 CACHE_DEBUG 활성화 시 X-Cache-Key 헤더 설정
+// 운영자가 응답과 Redis 항목을 대조할 때 사용
+func (h *ProxyHandler) setCacheKeyHeader(w http.ResponseWriter, r *http.Request, query string) {
+	if h.config.CacheDebug {
+		w.Header().Set("X-Cache-Key", h.cache.Key(cacheQuery(r.Context(), query)))
+	}
+}
+
+// sendCachedSSE는 캐시된 응답을 SSE 형식으로 전송
+// X-Cache 헤더는 호출하는 쪽에서 설정 (HIT / STALE)
+// 원본 청크가 저장된 항목은 같은 경계로 재생하고, 없으면 응답 텍스트를 나눠서 전송
+func (h *ProxyHandler) sendCachedSSE(w http.ResponseWriter, r *http.Request, query string, cached *cache.CachedResponse) {
+	sw, ok := h.newSSEWriter(w, r)
 	if !ok {
-		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		middleware.WriteError(w, r, http.StatusInternalServerError, "Streaming not supported")
 		return
 	}
+	defer sw.Close()
+
+	chunks := cached.Chunks
+	if len(chunks) == 0 {
+		chunks = splitRunes(cached.Response, cachedChunkSize)
+	}
 
-	// 캐시된 응답을 청크로 나눠서 스트리밍 효과 유지
-	chunkSize := 20
-	for i := 0; i < len(response); i += chunkSize {
-		end := min(i+chunkSize, len(response))
-		chunk := response[i:end]
+	for _, chunk := range chunks {
+		if err := sw.send(h.formatData(chunk) + "\n"); err != nil {
+			return // 클라이언트 연결 종료
+		}
 
-		fmt.Fprintf(w, "data:%s\n\n", chunk)
-		flusher.Flush()
-		time.Sleep(10 * time.Millisecond) // 자연스러운 스트리밍 효과
+		// 자연스러운 스트리밍 효과 (클라이언트가 끊으면 즉시 중단)
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	// 최종 결과 이벤트 (SSE_RESULT_EVENT, Backend 결과 이벤트 필드는 캐시에 저장되지 않음)
+	if h.config.SSEResultEvent {
+		if err := sw.send(h.formatResult(query, cached.Response, nil, map[string]any{
+			"cached":      true,
+			"cached_at":   cached.CreatedAt,
+			"age_seconds": cacheAge(cached),
+			"chunks":      len(chunks),
+		})); err != nil {
+			return
+		}
 	}
 
 	// 완료 이벤트
-	fmt.Fprint(w, "event:done\ndata:[DONE]\n\n")
-	flusher.Flush()
+	sw.send("event:done\ndata:[DONE]\n\n")
+}
+
+// cachedChunkSize는 청크 정보가 없는 캐시 항목을 재생할 때의 청크 크기 (rune 수)
+const cachedChunkSize = 20
+
+// splitRunes는 텍스트를 size rune 단위로 분할
+// (한글 등 멀티바이트 문자가 잘리지 않도록 rune 단위로 분할)
+func splitRunes(text string, size int) []string {
+	runes := []rune(text)
+	chunks := make([]string, 0, len(runes)/size+1)
+	for i := 0; i < len(runes); i += size {
+		chunks = append(chunks, string(runes[i:min(i+size, len(runes))]))
+	}
+	return chunks
 }
 
 // responseRecorder는 응답을 캡처하기 위한 래퍼
@@ -275,12 +843,18 @@ func (rec *responseRecorder) Write(b []byte) (int, error) {
 	return rec.ResponseWriter.Write(b)
 }
 
+// toSet은 문자열 목록을 조회용 집합으로 변환
+func toSet(items []string) map[string]bool {
+	set := make(map[string]bool, len(items))
+	for _, item := range items {
+		set[item] = true
+	}
+	return set
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
 	}
 	return b
 }
-
-
-
@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/devbrain/gateway/internal/cache"
+	"github.com/devbrain/gateway/internal/config"
+)
+
+// newTestHandler는 backend를 대상으로 하는 인메모리 캐시 ProxyHandler 생성
+// 설정은 환경 변수에서 읽으므로 호출 전에 t.Setenv로 지정
+func newTestHandler(t *testing.T, backend http.Handler) *ProxyHandler {
+	t.Helper()
+	be := httptest.NewServer(backend)
+	t.Cleanup(be.Close)
+	return NewProxyHandler(be.URL, cache.NewMemoryCache(100), config.Load())
+}
+
+// postChat은 동기 채팅 요청을 보내고 응답 반환
+func postChat(h http.Handler, query string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(map[string]string{"query": query})
+	req := httptest.NewRequest(http.MethodPost, "/api/chat", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+// getStream은 스트림 채팅 요청을 보내고 data 이벤트를 이어 붙인 텍스트와 응답 반환
+func getStream(h http.Handler, query string) (string, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodGet, "/api/chat/stream?q="+url.QueryEscape(query), nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	var text strings.Builder
+	for _, event := range strings.Split(rec.Body.String(), "\n\n") {
+		var data []string
+		named := false
+		for _, line := range strings.Split(event, "\n") {
+			if strings.HasPrefix(line, "event:") {
+				named = true
+			}
+			if value, ok := strings.CutPrefix(line, "data:"); ok && value != "[DONE]" {
+				data = append(data, value)
+			}
+		}
+		if !named && len(data) > 0 {
+			text.WriteString(strings.Join(data, "\n"))
+		}
+	}
+	return text.String(), rec
+}
+
+func TestNormalizeTrailingSlash(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/api/chat", "/api/chat"},
+		{"/api/chat/", "/api/chat"},
+		{"/api/chat//", "/api/chat"},
+		{"/api/chat/stream/", "/api/chat/stream"},
+		{"/healthz/ready/", "/healthz/ready"},
+		{"/metrics/", "/metrics"},
+		{"/", "/"},
+		// 접두사 라우트와 알 수 없는 경로는 그대로
+		{"/api/", "/api/"},
+		{"/api/documents/", "/api/documents/"},
+		{"/swagger/", "/swagger/"},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", tt.path, nil)
+		normalizeTrailingSlash(r)
+		if r.URL.Path != tt.want {
+			t.Errorf("normalizeTrailingSlash(%q) = %q, want %q", tt.path, r.URL.Path, tt.want)
+		}
+	}
+}
+
+func TestCacheSharedAcrossSyncAndStream(t *testing.T) {
+	const answer = "첫 번째 줄입니다.\n두 번째 줄: Go 게이트웨이"
+	tokens := []string{"첫 번째 ", "줄입니다.\n두 번째 줄: ", "Go 게이트웨이"}
+
+	var calls atomic.Int64
+	h := newTestHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.URL.Path == "/api/chat/stream" {
+			w.Header().Set("Content-Type", "text/event-stream")
+			for _, token := range tokens {
+				for _, line := range strings.Split(token, "\n") {
+					fmt.Fprintf(w, "data:%s\n", line)
+				}
+				fmt.Fprint(w, "\n")
+			}
+			fmt.Fprint(w, "data:[DONE]\n\n")
+			return
+		}
+		io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"response": answer + "\n"})
+	}))
+
+	t.Run("sync then stream", func(t *testing.T) {
+		calls.Store(0)
+		if rec := postChat(h, "동기 먼저"); rec.Code != http.StatusOK || rec.Header().Get("X-Cache") != "MISS" {
+			t.Fatalf("sync: status %d, X-Cache %q", rec.Code, rec.Header().Get("X-Cache"))
+		}
+
+		text, rec := getStream(h, "동기 먼저")
+		if rec.Header().Get("X-Cache") != "HIT" {
+			t.Fatalf("stream X-Cache = %q, want HIT", rec.Header().Get("X-Cache"))
+		}
+		if text != answer {
+			t.Errorf("stream text = %q, want %q", text, answer)
+		}
+		if !strings.Contains(rec.Body.String(), "data:[DONE]") {
+			t.Error("stream replay missing done event")
+		}
+		if n := calls.Load(); n != 1 {
+			t.Errorf("backend calls = %d, want 1", n)
+		}
+	})
+
+	t.Run("stream then sync", func(t *testing.T) {
+		calls.Store(0)
+		if text, rec := getStream(h, "스트림 먼저"); text != answer || rec.Header().Get("X-Cache") != "MISS" {
+			t.Fatalf("stream: text %q, X-Cache %q", text, rec.Header().Get("X-Cache"))
+		}
+
+		rec := postChat(h, "스트림 먼저")
+		if rec.Header().Get("X-Cache") != "HIT" {
+			t.Fatalf("sync X-Cache = %q, want HIT", rec.Header().Get("X-Cache"))
+		}
+		var body struct {
+			Response string `json:"response"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("sync body: %v", err)
+		}
+		if body.Response != answer {
+			t.Errorf("sync response = %q, want %q", body.Response, answer)
+		}
+		if n := calls.Load(); n != 1 {
+			t.Errorf("backend calls = %d, want 1", n)
+		}
+	})
+}
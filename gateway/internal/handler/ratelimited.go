@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/devbrain/gateway/internal/middleware"
+)
+
+// serveRateLimited는 한도를 초과한 요청 처리 (RATE_LIMIT_CACHE_FALLBACK)
+// 채팅 요청의 캐시 히트는 X-RateLimited: true와 함께 응답하고, 그 외에는 429 반환
+// Backend와 임베딩 API는 호출하지 않으므로(정확 일치 캐시만 조회) 한도를 초과한 클라이언트가 유료 호출을 늘리지 않는다.
+func (h *ProxyHandler) serveRateLimited(w http.ResponseWriter, r *http.Request) {
+	var query string
+	stream := false
+	switch {
+	case r.URL.Path == "/api/chat/stream" && r.Method == http.MethodGet:
+		query = r.URL.Query().Get(h.config.QueryParamName)
+		stream = true
+	case r.URL.Path == "/api/chat" && r.Method == http.MethodPost:
+		if body, err := h.readRequestBody(r); err == nil {
+			query = h.extractQuery(body)
+		}
+	}
+
+	r, query = h.redactQuery(r, query)
+
+	r, ok := h.withClientCacheKey(w, r)
+	if !ok {
+		return
+	}
+
+	if query != "" {
+		if cached := h.getExact(r.Context(), query); cached != nil && (!stream || cached.StatusCode() == http.StatusOK) {
+			log.Printf("💾 Rate Limit 초과, 캐시로 응답: %s", query[:min(30, len(query))])
+			w.Header().Set("X-RateLimited", "true")
+			middleware.AddLogField(r.Context(), "rate_limited", "true")
+			if stream {
+				h.setCacheKeyHeader(w, r, query)
+				h.setCacheHit(w, r, "HIT", cached)
+				h.recordHit(w, cached)
+				h.sendCachedSSE(w, r, query, cached)
+				return
+			}
+			h.writeSyncHit(w, r, query, cached, 1.0)
+			return
+		}
+	}
+
+	middleware.WriteTooManyRequests(w, r)
+}
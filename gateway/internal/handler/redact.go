@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// 쿼리 마스킹 후 Backend로 전달할 쿼리 (QUERY_REDACT_FORWARD)
+const (
+	redactForwardRedacted = "redacted" // 마스킹된 쿼리 전달 (기본값)
+	redactForwardOriginal = "original" // 원본 쿼리 전달 (로그/캐시 키만 마스킹)
+)
+
+// redactRuleConfig는 QUERY_REDACT_FILE의 마스킹 규칙 하나
+type redactRuleConfig struct {
+	Name        string `json:"name"`
+	Pattern     string `json:"pattern"`
+	Replacement string `json:"replacement"`
+}
+
+// builtinRedactRules는 QUERY_REDACT에서 이름으로 켤 수 있는 기본 규칙
+var builtinRedactRules = map[string]redactRuleConfig{
+	"email": {Name: "email", Pattern: `[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`, Replacement: "[EMAIL]"},
+	"phone": {Name: "phone", Pattern: `(?:\+\d{1,3}[-.\s]?)?\(?\b\d{2,4}\)?[-.\s]?\d{3,4}[-.\s]?\d{4}\b`, Replacement: "[PHONE]"},
+}
+
+// redactRule은 컴파일된 마스킹 규칙
+type redactRule struct {
+	name        string
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// queryRedactor는 채팅 쿼리의 개인정보를 정규식 규칙 순서대로 치환
+// 마스킹된 쿼리를 로그, 캐시 키, 시맨틱 임베딩에 사용하고 Backend에는 QUERY_REDACT_FORWARD에 따라 전달
+type queryRedactor struct {
+	rules           []redactRule
+	forwardOriginal bool
+}
+
+// loadQueryRedactor는 기본 규칙 이름(QUERY_REDACT)과 규칙 파일(QUERY_REDACT_FILE, JSON 배열)로 queryRedactor 생성
+// 규칙이 하나도 없으면 nil
+func loadQueryRedactor(names []string, path, forward string) (*queryRedactor, error) {
+	var configs []redactRuleConfig
+	for _, name := range names {
+		rule, ok := builtinRedactRules[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown built-in rule %q (email, phone)", name)
+		}
+		configs = append(configs, rule)
+	}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var fileRules []redactRuleConfig
+		if err := json.Unmarshal(data, &fileRules); err != nil {
+			return nil, err
+		}
+		configs = append(configs, fileRules...)
+	}
+	if len(configs) == 0 {
+		return nil, nil
+	}
+
+	qr := &queryRedactor{}
+	switch forward {
+	case "", redactForwardRedacted:
+	case redactForwardOriginal:
+		qr.forwardOriginal = true
+	default:
+		return nil, fmt.Errorf("unknown forward mode %q (redacted, original)", forward)
+	}
+
+	for i, rc := range configs {
+		if rc.Pattern == "" {
+			return nil, fmt.Errorf("rule %d (%s): pattern is required", i, rc.Name)
+		}
+		pattern, err := regexp.Compile(rc.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d (%s): %w", i, rc.Name, err)
+		}
+		replacement := rc.Replacement
+		if replacement == "" {
+			replacement = "[REDACTED]"
+		}
+		qr.rules = append(qr.rules, redactRule{name: rc.Name, pattern: pattern, replacement: replacement})
+	}
+	return qr, nil
+}
+
+// redact는 모든 규칙을 순서대로 적용한 쿼리 반환 (nil이면 그대로)
+// 치환 문자열은 그대로 사용 ($1 같은 그룹 참조는 확장하지 않음)
+func (qr *queryRedactor) redact(query string) string {
+	if qr == nil {
+		return query
+	}
+	for _, rule := range qr.rules {
+		query = rule.pattern.ReplaceAllLiteralString(query, rule.replacement)
+	}
+	return query
+}
+
+// RedactText는 QUERY_REDACT 규칙으로 임의의 텍스트를 마스킹 (샘플 상세 로그 바디 등, 규칙이 없으면 그대로)
+func (h *ProxyHandler) RedactText(text string) string {
+	return h.redactor.redact(text)
+}
+
+// redactQuery는 QUERY_REDACT 규칙으로 쿼리를 마스킹하고, 원본을 Backend 전달용으로 요청 컨텍스트에 저장
+// 반환한 쿼리를 로그/캐시 키에 사용한다.
+// QUERY_REDACT_FORWARD=original이면 응답은 원본 질문에 대한 답이므로, 마스킹 결과만 같은 다른 사람의 질문과
+// 응답을 공유하지 않도록 마스킹된 요청은 캐시를 거치지 않는다 (요청 합치기는 원본 해시로 구분, flightKey).
+func (h *ProxyHandler) redactQuery(r *http.Request, query string) (*http.Request, string) {
+	redacted := h.redactor.redact(query)
+	if redacted == query {
+		return r, query
+	}
+	ctx := context.WithValue(r.Context(), originalQueryContextKey, query)
+	if h.redactor.forwardOriginal {
+		ctx = context.WithValue(ctx, cacheDisabledContextKey, true)
+	}
+	return r.WithContext(ctx), redacted
+}
+
+// originalQueryHash는 QUERY_REDACT_FORWARD=original로 원본이 Backend에 전달되는 요청의 원본 쿼리 해시 (아니면 빈 문자열)
+func (h *ProxyHandler) originalQueryHash(ctx context.Context) string {
+	if h.redactor == nil || !h.redactor.forwardOriginal {
+		return ""
+	}
+	original, ok := ctx.Value(originalQueryContextKey).(string)
+	if !ok {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(original))
+	return hex.EncodeToString(sum[:])
+}
+
+// backendQuery는 Backend에 전달할 쿼리 (QUERY_REDACT_FORWARD=original이면 마스킹 전 원본)
+func (h *ProxyHandler) backendQuery(ctx context.Context, query string) string {
+	if h.redactor == nil || !h.redactor.forwardOriginal {
+		return query
+	}
+	if original, ok := ctx.Value(originalQueryContextKey).(string); ok {
+		return original
+	}
+	return query
+}
+
+// redactBody는 동기 채팅 바디의 쿼리 필드(QUERY_JSON_FIELD)를 마스킹된 쿼리로 교체
+// 마스킹되지 않았거나 QUERY_REDACT_FORWARD=original이면 원본 바디 그대로 반환
+func (h *ProxyHandler) redactBody(ctx context.Context, body []byte, query string) []byte {
+	if _, redacted := ctx.Value(originalQueryContextKey).(string); !redacted || h.redactor.forwardOriginal {
+		return body
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return body
+	}
+	encoded, err := json.Marshal(query)
+	if err != nil {
+		return body
+	}
+	fields[h.config.QueryJSONField] = encoded
+	redacted, err := json.Marshal(fields)
+	if err != nil {
+		return body
+	}
+	return redacted
+}
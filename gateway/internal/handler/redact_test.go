@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestQueryRedactorRules(t *testing.T) {
+	dir := t.TempDir()
+	rulesFile := filepath.Join(dir, "rules.json")
+	rules := `[
+		{"name": "rrn", "pattern": "\\d{6}-?[1-4]\\d{6}", "replacement": "[RRN]"},
+		{"name": "ticket", "pattern": "TICKET-\\d+"},
+		{"name": "literal", "pattern": "secret-(\\w+)", "replacement": "$1"}
+	]`
+	if err := os.WriteFile(rulesFile, []byte(rules), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		names []string
+		file  string
+		query string
+		want  string
+	}{
+		{"email", []string{"email"}, "", "메일은 dev.brain+rag@example.co.kr 입니다", "메일은 [EMAIL] 입니다"},
+		{"multiple emails", []string{"email"}, "", "a@b.io, c@d.com", "[EMAIL], [EMAIL]"},
+		{"email rule ignores phone", []string{"email"}, "", "010-1234-5678", "010-1234-5678"},
+		{"mobile", []string{"phone"}, "", "연락처 010-1234-5678", "연락처 [PHONE]"},
+		{"international", []string{"phone"}, "", "call +82 10 1234 5678", "call [PHONE]"},
+		{"seoul landline", []string{"phone"}, "", "02-123-4567로 전화", "[PHONE]로 전화"},
+		{"short number is not phone", []string{"phone"}, "", "RAG 2024 버전", "RAG 2024 버전"},
+		{"builtin names are case-insensitive", []string{"EMAIL", "Phone"}, "", "a@b.io / 010 1234 5678", "[EMAIL] / [PHONE]"},
+		{"custom rule", nil, rulesFile, "주민번호 900101-1234567", "주민번호 [RRN]"},
+		{"custom default replacement", nil, rulesFile, "TICKET-42 상태", "[REDACTED] 상태"},
+		{"custom replacement is literal", nil, rulesFile, "secret-abc", "$1"},
+		{"builtin then custom", []string{"email"}, rulesFile, "a@b.io TICKET-7", "[EMAIL] [REDACTED]"},
+		{"no match", []string{"email", "phone"}, rulesFile, "RAG란 무엇인가", "RAG란 무엇인가"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qr, err := loadQueryRedactor(tt.names, tt.file, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := qr.redact(tt.query); got != tt.want {
+				t.Errorf("redact(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadQueryRedactorErrors(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name    string
+		names   []string
+		file    string
+		forward string
+	}{
+		{"unknown builtin", []string{"ssn"}, "", ""},
+		{"missing pattern", nil, write("missing.json", `[{"name": "x"}]`), ""},
+		{"invalid pattern", nil, write("invalid.json", `[{"name": "x", "pattern": "("}]`), ""},
+		{"invalid json", nil, write("broken.json", `{`), ""},
+		{"missing file", nil, filepath.Join(dir, "none.json"), ""},
+		{"unknown forward mode", []string{"email"}, "", "plain"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := loadQueryRedactor(tt.names, tt.file, tt.forward); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+
+	if qr, err := loadQueryRedactor(nil, "", "original"); err != nil || qr != nil {
+		t.Errorf("no rules: got %v, %v, want nil redactor", qr, err)
+	}
+}
+
+// echoQueryBackend는 받은 쿼리를 그대로 답변으로 돌려주는 Backend (호출 수 집계)
+func echoQueryBackend(calls *atomic.Int64) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"response": "질문: " + body["query"]})
+	})
+}
+
+func TestRedactForwardOriginalDoesNotShareAnswers(t *testing.T) {
+	t.Setenv("QUERY_REDACT", "email")
+	t.Setenv("QUERY_REDACT_FORWARD", "original")
+	var calls atomic.Int64
+	h := newTestHandler(t, echoQueryBackend(&calls))
+
+	for _, email := range []string{"alice@example.com", "bob@example.com", "alice@example.com"} {
+		rec := postChat(h, "내 계정 "+email+" 상태")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: status %d", email, rec.Code)
+		}
+		if !strings.Contains(rec.Body.String(), email) {
+			t.Errorf("%s: answer %q is not for the original query", email, rec.Body.String())
+		}
+		if rec.Header().Get("X-Cache") == "HIT" {
+			t.Errorf("%s: served from cache", email)
+		}
+	}
+	if n := calls.Load(); n != 3 {
+		t.Errorf("backend calls = %d, want 3 (redacted queries are not cached)", n)
+	}
+
+	// 마스킹되지 않은 쿼리는 그대로 캐시
+	postChat(h, "일반 질문")
+	if rec := postChat(h, "일반 질문"); rec.Header().Get("X-Cache") != "HIT" {
+		t.Errorf("unredacted query X-Cache = %q, want HIT", rec.Header().Get("X-Cache"))
+	}
+}
+
+func TestRedactForwardRedactedSharesCache(t *testing.T) {
+	t.Setenv("QUERY_REDACT", "email")
+	var calls atomic.Int64
+	h := newTestHandler(t, echoQueryBackend(&calls))
+
+	first := postChat(h, "내 계정 alice@example.com 상태")
+	if strings.Contains(first.Body.String(), "alice") || !strings.Contains(first.Body.String(), "[EMAIL]") {
+		t.Errorf("backend received unredacted query: %q", first.Body.String())
+	}
+	if rec := postChat(h, "내 계정 bob@example.com 상태"); rec.Header().Get("X-Cache") != "HIT" {
+		t.Errorf("X-Cache = %q, want HIT (same redacted query)", rec.Header().Get("X-Cache"))
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("backend calls = %d, want 1", n)
+	}
+}
+
+func TestFlightKeySeparatesOriginalQueries(t *testing.T) {
+	t.Setenv("QUERY_REDACT", "email")
+	t.Setenv("QUERY_REDACT_FORWARD", "original")
+	h := newTestHandler(t, http.NotFoundHandler())
+
+	key := func(query string) string {
+		r, redacted := h.redactQuery(httptest.NewRequest(http.MethodPost, "/api/chat", nil), query)
+		return h.flightKey(r, redacted)
+	}
+	if key("a@b.io 상태") == key("c@d.io 상태") {
+		t.Error("different originals share a flight key")
+	}
+	if key("a@b.io 상태") != key("a@b.io 상태") {
+		t.Error("same original has different flight keys")
+	}
+}
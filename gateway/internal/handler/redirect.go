@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/devbrain/gateway/internal/middleware"
+)
+
+// maxBackendRedirects는 FOLLOW_REDIRECTS로 따라가는 최대 리다이렉트 수
+const maxBackendRedirects = 10
+
+// isRedirect는 Location으로 이동을 요구하는 Backend 응답인지 확인 (300, 304 제외)
+func isRedirect(code int) bool {
+	switch code {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+	return false
+}
+
+// checkRedirect는 Backend 채팅 요청의 리다이렉트 정책 (backendClient의 CheckRedirect)
+// FOLLOW_REDIRECTS가 꺼져 있거나 다른 호스트로 이동하면 (Backend 인증 헤더가 외부로 전달되지 않도록)
+// 따라가지 않고 리다이렉트 응답을 그대로 반환한다.
+func (h *ProxyHandler) checkRedirect(req *http.Request, via []*http.Request) error {
+	if !h.config.FollowRedirects || req.URL.Host != via[0].URL.Host {
+		return http.ErrUseLastResponse
+	}
+	if len(via) >= maxBackendRedirects {
+		log.Printf("⚠️ Backend 리다이렉트가 %d회를 넘어 중단: %s", maxBackendRedirects, req.URL.Path)
+		return http.ErrUseLastResponse
+	}
+	return nil
+}
+
+// followRedirect는 FOLLOW_REDIRECTS 설정 시 리버스 프록시가 받은 Backend 리다이렉트를 서버 측에서 따라가
+// 최종 응답으로 교체 (ModifyResponse)
+// 다른 호스트로의 이동이나 다시 보낼 수 없는 바디의 307/308은 그대로 두며, 이동 후 요청은 backendClient 정책을 따른다.
+func (h *ProxyHandler) followRedirect(resp *http.Response) error {
+	req := resp.Request
+	if !h.config.FollowRedirects || req == nil || !isRedirect(resp.StatusCode) {
+		return nil
+	}
+	loc, err := req.URL.Parse(resp.Header.Get("Location"))
+	if err != nil || resp.Header.Get("Location") == "" || loc.Host != req.URL.Host {
+		return nil
+	}
+
+	// 301/302/303은 GET으로 바꿔 바디 없이, 307/308은 같은 메서드와 바디로 다시 요청 (net/http와 같은 규칙)
+	method, getBody := req.Method, req.GetBody
+	if resp.StatusCode <= http.StatusSeeOther {
+		if method != http.MethodHead {
+			method = http.MethodGet
+		}
+		getBody = nil
+	} else if req.ContentLength != 0 && getBody == nil {
+		return nil
+	}
+
+	next, err := http.NewRequestWithContext(req.Context(), method, loc.String(), nil)
+	if err != nil {
+		return nil
+	}
+	// Location 재작성(REWRITE_LOCATION)이 클라이언트가 보낸 호스트를 계속 사용하도록 Host 유지
+	next.Header = req.Header.Clone()
+	next.Host = req.Host
+	if getBody != nil {
+		if next.Body, err = getBody(); err != nil {
+			return err
+		}
+		next.GetBody, next.ContentLength = getBody, req.ContentLength
+	} else {
+		next.Header.Del("Content-Type")
+		next.Header.Del("Content-Length")
+		next.Header.Del("Content-Encoding")
+	}
+
+	followed, err := h.backendClient.Do(next)
+	if err != nil {
+		return err
+	}
+	log.Printf("↪️ Backend 리다이렉트 따라감 (%d → %s)", resp.StatusCode, followed.Request.URL.Path)
+
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	*resp = *followed
+	return nil
+}
+
+// writeStreamRedirect는 Backend가 스트림 대신 리다이렉트(인증 페이지 등)를 반환했을 때
+// 리다이렉트 본문을 스트리밍하지 않고 SSE 에러 이벤트로 알림
+func (h *ProxyHandler) writeStreamRedirect(w http.ResponseWriter, r *http.Request, resp *http.Response) {
+	log.Printf("↪️ Backend가 스트림 요청을 리다이렉트 (%d → %s)", resp.StatusCode, resp.Header.Get("Location"))
+
+	h.setCacheMiss(w, r, false)
+	sw, ok := h.newSSEWriter(w, r)
+	if !ok {
+		middleware.WriteError(w, r, http.StatusInternalServerError, "Streaming not supported")
+		return
+	}
+	defer sw.Close()
+
+	data, _ := json.Marshal(map[string]any{
+		"error":   "backend_redirect",
+		"status":  resp.StatusCode,
+		"message": "Backend가 스트림 대신 리다이렉트를 반환했습니다.",
+	})
+	sw.send("event:error\ndata:" + string(data) + "\n\n")
+}
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/devbrain/gateway/internal/cache"
+)
+
+// freshnessProbes는 캐시 신선도 검사 상태와 집계 (CACHE_REFRESH_PROBABILITY, /api/cache/stats)
+type freshnessProbes struct {
+	inflight sync.Map // 검사 중인 캐시 키 (같은 항목을 동시에 여러 번 검사하지 않음)
+
+	probes  atomic.Int64 // 시작한 검사 수
+	drifts  atomic.Int64 // 답변이 달라진 항목 수
+	updated atomic.Int64 // 새 답변으로 갱신한 항목 수
+	failed  atomic.Int64 // Backend 에러 등으로 비교하지 못한 검사 수
+}
+
+// snapshot은 통계 응답용 검사 집계
+func (p *freshnessProbes) snapshot() map[string]int64 {
+	return map[string]int64{
+		"probes":  p.probes.Load(),
+		"drifts":  p.drifts.Load(),
+		"updated": p.updated.Load(),
+		"failed":  p.failed.Load(),
+	}
+}
+
+// probeFreshness는 캐시 히트 중 CACHE_REFRESH_PROBABILITY 비율을 골라 백그라운드에서 Backend에 다시 질의
+// 클라이언트 응답은 이미 캐시로 처리했으므로 기다리지 않으며, 정확히 일치한 포지티브 항목만 검사한다.
+// body는 동기 요청의 Backend 전달 바디 (스트림 요청이면 nil)
+func (h *ProxyHandler) probeFreshness(r *http.Request, query string, body []byte, cached *cache.CachedResponse, score float64) {
+	if h.config.CacheRefreshProbability <= 0 || score < 1 || cached.StatusCode() != http.StatusOK || cached.Negative {
+		return
+	}
+	if rand.Float64() >= h.config.CacheRefreshProbability || h.degradedReason() != "" {
+		return
+	}
+
+	key := h.cache.Key(cacheQuery(r.Context(), query))
+	if _, running := h.freshness.inflight.LoadOrStore(key, struct{}{}); running {
+		return
+	}
+
+	// 클라이언트 연결과 무관하게 진행하고, Backend 응답은 일반 경로로 캐시되지 않도록 캐시를 끈 컨텍스트 사용
+	ctx := context.WithValue(context.WithoutCancel(r.Context()), cacheDisabledContextKey, true)
+	req := withQuery(r.Clone(ctx), query)
+	go func() {
+		defer h.freshness.inflight.Delete(key)
+		h.runFreshnessProbe(req, query, body, cached)
+	}()
+}
+
+// runFreshnessProbe는 Backend 답변을 캐시된 답변과 비교하고, CACHE_REFRESH_MIN_SIMILARITY보다 덜 비슷하면
+// 드리프트로 기록한 뒤 새 답변으로 캐시 갱신 (Backend Cache-Control과 Backend 상태는 일반 저장과 같이 따름)
+func (h *ProxyHandler) runFreshnessProbe(r *http.Request, query string, body []byte, cached *cache.CachedResponse) {
+	short := query[:min(30, len(query))]
+	h.freshness.probes.Add(1)
+
+	buf := newBufferedResponse()
+	var (
+		answer string
+		chunks []string
+		header http.Header
+	)
+	if body == nil || h.config.SyncViaStream {
+		collector, streamHeader, ok := h.collectStream(buf, r, query)
+		if !ok {
+			h.freshness.failed.Add(1)
+			log.Printf("⚠️ 캐시 신선도 검사 실패 (상태 %d): %s", buf.statusCode, short)
+			return
+		}
+		answer, chunks, header = collector.text.String(), collector.chunks, streamHeader
+	} else {
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		r.ContentLength = int64(len(body))
+		h.serveProxy(buf, r)
+
+		var err error
+		if buf.statusCode == http.StatusOK {
+			answer, _, err = h.resolveAnswer(buf.body.Bytes())
+		}
+		if buf.statusCode != http.StatusOK || err != nil {
+			h.freshness.failed.Add(1)
+			log.Printf("⚠️ 캐시 신선도 검사 실패 (상태 %d): %s", buf.statusCode, short)
+			return
+		}
+		header = buf.header
+	}
+
+	similarity := answerSimilarity(cached.Response, cache.NormalizeResponse(answer))
+	if similarity >= h.config.CacheRefreshMinSimilarity {
+		log.Printf("🔍 캐시 신선도 확인 (유사도 %.2f): %s", similarity, short)
+		return
+	}
+	h.freshness.drifts.Add(1)
+	log.Printf("🔀 캐시 답변 변경 감지 (유사도 %.2f, 캐시 시각 %s): %s", similarity, cached.CreatedAt.Format("2006-01-02 15:04:05"), short)
+
+	ttl, store := h.backendCacheTTL(header)
+	switch {
+	case !store:
+		log.Printf("⏭️ 캐시 갱신 안 함 (Backend Cache-Control): %s", short)
+		return
+	case strings.TrimSpace(answer) == "":
+		log.Printf("⏭️ 캐시 갱신 안 함 (빈 답변): %s", short)
+		return
+	}
+
+	var err error
+	if chunks != nil && h.config.SSEStoreChunks {
+		err = h.setChunks(cacheQuery(r.Context(), query), chunks, ttl)
+	} else {
+		err = h.cache.Set(cacheQuery(r.Context(), query), answer, ttl)
+	}
+	if err != nil {
+		log.Printf("⚠️ 캐시 갱신 실패: %v", err)
+		return
+	}
+	h.freshness.updated.Add(1)
+	log.Printf("💾 캐시 갱신 (신선도 검사): %s", short)
+}
+
+// answerSimilarity는 두 답변의 단어 집합 Jaccard 유사도 (0~1, 대소문자와 공백 차이는 무시)
+// 생성 모델은 같은 내용도 표현이 조금씩 달라지므로 완전 일치 대신 겹치는 단어 비율로 비교한다.
+func answerSimilarity(a, b string) float64 {
+	wordsA, wordsB := wordSet(a), wordSet(b)
+	if len(wordsA) == 0 && len(wordsB) == 0 {
+		return 1
+	}
+
+	common := 0
+	for word := range wordsA {
+		if wordsB[word] {
+			common++
+		}
+	}
+	return float64(common) / float64(len(wordsA)+len(wordsB)-common)
+}
+
+// wordSet은 소문자로 바꾼 텍스트의 공백 구분 단어 집합
+func wordSet(text string) map[string]bool {
+	words := make(map[string]bool)
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		words[word] = true
+	}
+	return words
+}
@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// rewriteBackendRequest는 Backend로 나가는 요청을 재작성
+// 리버스 프록시의 Director와 직접 만든 SSE 요청 모두에 적용된다.
+func (h *ProxyHandler) rewriteBackendRequest(req *http.Request, clientIP string) {
+	// Gateway 전용 제어 헤더는 Backend로 전달하지 않음
+	req.Header.Del(adminTokenHeader)
+	req.Header.Del("X-Backend-Target")
+	req.Header.Del(requestTimeoutHeader)
+
+	h.filterRequestHeaders(req)
+	stripHeaders(req.Header, h.config.StripRequestHeaders)
+	h.setClientIPHeaders(req, clientIP)
+	h.injectBackendAuth(req)
+}
+
+// clientIP는 Backend로 전달할 클라이언트 IP (신뢰하는 프록시 뒤라면 X-Forwarded-For에서 결정)
+func (h *ProxyHandler) clientIP(r *http.Request) string {
+	if h.clientIPs != nil {
+		if ip := h.clientIPs.ClientIP(r); ip != nil {
+			return ip.String()
+		}
+	}
+	return remoteHost(r.RemoteAddr)
+}
+
+// remoteHost는 "host:port" 형식의 주소에서 host만 반환
+func remoteHost(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+// setClientIPHeaders는 X-Forwarded-For와 CLIENT_IP_HEADER를 클라이언트 IP 하나로 설정
+// 클라이언트가 보낸 값은 위조될 수 있으므로 덧붙이지 않고 교체한다.
+func (h *ProxyHandler) setClientIPHeaders(req *http.Request, clientIP string) {
+	if h.config.ClientIPHeader != "" {
+		req.Header.Del(h.config.ClientIPHeader)
+	}
+	if clientIP == "" {
+		req.Header.Del("X-Forwarded-For")
+		return
+	}
+
+	req.Header.Set("X-Forwarded-For", clientIP)
+	if h.config.ClientIPHeader != "" {
+		req.Header.Set(h.config.ClientIPHeader, clientIP)
+	}
+}
+
+// withClientAddr는 RemoteAddr를 결정된 클라이언트 IP로 바꾼 요청 사본 반환
+// ReverseProxy는 RemoteAddr로 X-Forwarded-For를 설정하므로 프록시 경로에서도 같은 IP가 전달된다.
+func (h *ProxyHandler) withClientAddr(r *http.Request) *http.Request {
+	ip := h.clientIP(r)
+	if ip == "" {
+		return r
+	}
+	_, port, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		port = "0"
+	}
+
+	clone := *r
+	clone.RemoteAddr = net.JoinHostPort(ip, port)
+	return &clone
+}
+
+// injectBackendAuth는 클라이언트가 보낸 인증 헤더를 제거하고 Gateway의 Backend API 키를 주입
+// Backend 자격 증명은 Gateway만 보유하며 클라이언트에 노출되지 않는다.
+func (h *ProxyHandler) injectBackendAuth(req *http.Request) {
+	if h.config.BackendAPIKey == "" {
+		return
+	}
+
+	header := h.config.BackendAPIKeyHeader
+	req.Header.Del(header)
+
+	if strings.EqualFold(header, "Authorization") {
+		req.Header.Set(header, "Bearer "+h.config.BackendAPIKey)
+		return
+	}
+	req.Header.Set(header, h.config.BackendAPIKey)
+}
+
+// essentialRequestHeaders는 FORWARD_HEADER_ALLOWLIST 설정 시에도 항상 전달하는 요청 헤더
+// (바디 해석/응답 협상과 gRPC 트레일러에 필요, X-Forwarded-For 등 Gateway가 설정하는 헤더는 필터링 후 추가됨)
+var essentialRequestHeaders = []string{"Accept", "Accept-Encoding", "Content-Encoding", "Content-Length", "Content-Type", "Te"}
+
+// newHeaderAllowlist는 FORWARD_HEADER_ALLOWLIST를 정규화된 헤더 이름 집합으로 변환 (비어 있으면 nil = 전체 전달)
+func newHeaderAllowlist(names []string) map[string]bool {
+	if len(names) == 0 {
+		return nil
+	}
+
+	allowlist := make(map[string]bool, len(names)+len(essentialRequestHeaders))
+	for _, name := range names {
+		allowlist[http.CanonicalHeaderKey(strings.TrimSpace(name))] = true
+	}
+	for _, name := range essentialRequestHeaders {
+		allowlist[name] = true
+	}
+	return allowlist
+}
+
+// filterRequestHeaders는 FORWARD_HEADER_ALLOWLIST가 설정된 경우 목록에 없는 요청 헤더 제거
+// Backend가 신뢰하는 헤더를 클라이언트가 위조해 보내는 것을 막음 (미설정 시 모두 전달)
+func (h *ProxyHandler) filterRequestHeaders(req *http.Request) {
+	if h.headerAllowlist == nil {
+		return
+	}
+
+	for name := range req.Header {
+		if !h.headerAllowlist[http.CanonicalHeaderKey(name)] {
+			delete(req.Header, name)
+		}
+	}
+}
+
+// copyAllowedHeaders는 직접 만든 SSE Backend 요청에 FORWARD_HEADER_ALLOWLIST에 명시된 클라이언트 헤더 복사
+// 미설정 시에는 기존처럼 클라이언트 헤더를 전달하지 않으며, 필수 헤더(Accept-Encoding 등)는 Gateway가 스트림을
+// 직접 읽으므로 복사하지 않는다 (복사한 뒤 rewriteBackendRequest에서 제어 헤더 제거와 필터링이 함께 적용됨).
+func (h *ProxyHandler) copyAllowedHeaders(dst, src http.Header) {
+	if h.headerAllowlist == nil {
+		return
+	}
+
+	for name, values := range src {
+		name = http.CanonicalHeaderKey(name)
+		if h.headerAllowlist[name] && !slices.Contains(essentialRequestHeaders, name) {
+			dst[name] = slices.Clone(values)
+		}
+	}
+}
+
+// filterQueryParams는 QUERY_PARAM_ALLOWLIST가 설정된 경우 목록에 없는 쿼리 파라미터 제거
+// 클라이언트가 주입한 추적/디버그 파라미터로부터 Backend를 보호 (미설정 시 모두 전달)
+func (h *ProxyHandler) filterQueryParams(req *http.Request) {
+	if len(h.queryParamAllowlist) == 0 || req.URL.RawQuery == "" {
+		return
+	}
+
+	query := req.URL.Query()
+	for name := range query {
+		if !h.queryParamAllowlist[name] {
+			query.Del(name)
+		}
+	}
+	req.URL.RawQuery = query.Encode()
+}
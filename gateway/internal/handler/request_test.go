@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestFilterRequestHeaders(t *testing.T) {
+	tests := []struct {
+		name      string
+		allowlist string
+		kept      []string
+		dropped   []string
+	}{
+		{"unset forwards all", "", []string{"X-Trace-Id", "X-Internal-User", "Cookie"}, nil},
+		{"allowlisted and essential kept", "x-trace-id", []string{"X-Trace-Id", "Content-Type", "Accept"}, []string{"X-Internal-User", "Cookie", "Authorization"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("FORWARD_HEADER_ALLOWLIST", tt.allowlist)
+			h := newTestHandler(t, http.NotFoundHandler())
+
+			req := httptest.NewRequest(http.MethodPost, "/api/chat", nil)
+			for _, name := range append(append([]string{}, tt.kept...), tt.dropped...) {
+				req.Header.Set(name, "v")
+			}
+			h.filterRequestHeaders(req)
+
+			for _, name := range tt.kept {
+				if req.Header.Get(name) == "" {
+					t.Errorf("%s dropped, want kept", name)
+				}
+			}
+			for _, name := range tt.dropped {
+				if req.Header.Get(name) != "" {
+					t.Errorf("%s kept, want dropped", name)
+				}
+			}
+		})
+	}
+}
+
+func TestForwardHeaderAllowlistOnBackendRequests(t *testing.T) {
+	t.Setenv("FORWARD_HEADER_ALLOWLIST", "X-Trace-Id")
+
+	var mu sync.Mutex
+	received := map[string]http.Header{}
+	h := newTestHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		received[r.URL.Path] = r.Header.Clone()
+		mu.Unlock()
+		io.Copy(io.Discard, r.Body)
+		if r.URL.Path == "/api/chat/stream" {
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "data:답변\n\ndata:[DONE]\n\n")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"response": "답변"})
+	}))
+
+	setHeaders := func(req *http.Request) {
+		req.Header.Set("X-Trace-Id", "trace-1")
+		req.Header.Set("X-Internal-User", "admin")
+		req.Header.Set("Cookie", "session=secret")
+		req.Header.Set("X-Forwarded-For", "1.2.3.4")
+		req.Header.Set("Accept-Encoding", "br")
+	}
+
+	// 동기 요청: 리버스 프록시 Director 경로
+	syncReq := httptest.NewRequest(http.MethodPost, "/api/chat", strings.NewReader(`{"query":"동기 헤더"}`))
+	syncReq.Header.Set("Content-Type", "application/json")
+	setHeaders(syncReq)
+	h.ServeHTTP(httptest.NewRecorder(), syncReq)
+
+	// 스트림 요청: 직접 만든 Backend 요청 경로
+	streamReq := httptest.NewRequest(http.MethodGet, "/api/chat/stream?q="+url.QueryEscape("스트림 헤더"), nil)
+	setHeaders(streamReq)
+	h.ServeHTTP(httptest.NewRecorder(), streamReq)
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, path := range []string{"/api/chat", "/api/chat/stream"} {
+		header, ok := received[path]
+		if !ok {
+			t.Errorf("%s: backend not called", path)
+			continue
+		}
+		if got := header.Get("X-Trace-Id"); got != "trace-1" {
+			t.Errorf("%s: X-Trace-Id = %q, want trace-1", path, got)
+		}
+		for _, name := range []string{"X-Internal-User", "Cookie"} {
+			if got := header.Get(name); got != "" {
+				t.Errorf("%s: %s = %q, want dropped", path, name, got)
+			}
+		}
+		// X-Forwarded-For는 클라이언트 값이 아니라 Gateway가 설정
+		if got := header.Get("X-Forwarded-For"); strings.Contains(got, "1.2.3.4") {
+			t.Errorf("%s: X-Forwarded-For = %q, want client value replaced", path, got)
+		}
+	}
+	if got := received["/api/chat"].Get("Content-Type"); got != "application/json" {
+		t.Errorf("sync Content-Type = %q, want application/json (always forwarded)", got)
+	}
+	// Gateway가 직접 읽는 SSE 스트림에는 클라이언트의 인코딩 협상을 전달하지 않음
+	if got := received["/api/chat/stream"].Get("Accept-Encoding"); got == "br" {
+		t.Errorf("stream Accept-Encoding = %q, want not copied from client", got)
+	}
+}
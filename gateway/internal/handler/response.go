@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// modifyResponse는 Backend 응답을 클라이언트로 전달하기 전에 정리
+// - STRIP_RESPONSE_HEADERS에 지정된 내부 헤더 제거
+// - FOLLOW_REDIRECTS 활성화 시 같은 호스트로의 리다이렉트를 서버 측에서 따라가 최종 응답 전달
+// - REWRITE_LOCATION 활성화 시 Backend 호스트를 가리키는 Location을 Gateway 호스트로 재작성
+func (h *ProxyHandler) modifyResponse(resp *http.Response) error {
+	if err := h.followRedirect(resp); err != nil {
+		return err
+	}
+	stripHeaders(resp.Header, h.config.StripResponseHeaders)
+
+	if h.config.RewriteLocation {
+		h.rewriteLocation(resp)
+	}
+
+	return nil
+}
+
+// rewriteLocation은 Backend 호스트 기준의 절대 Location 헤더를 Gateway 호스트로 변경
+// 상대 경로 Location은 그대로 둔다.
+func (h *ProxyHandler) rewriteLocation(resp *http.Response) {
+	if resp.Request == nil {
+		return
+	}
+	// NewSingleHostReverseProxy는 Host 헤더를 변경하지 않으므로 클라이언트가 보낸 호스트가 남아 있음
+	rewriteLocationHeader(resp.Header, resp.Request.URL.Host, resp.Request)
+}
+
+// rewriteLocationHeader는 backendHost를 가리키는 절대 Location을 클라이언트가 요청한 호스트(client.Host)로 변경
+func rewriteLocationHeader(header http.Header, backendHost string, client *http.Request) {
+	location := header.Get("Location")
+	if location == "" {
+		return
+	}
+
+	loc, err := url.Parse(location)
+	if err != nil || loc.Host != backendHost {
+		return
+	}
+
+	loc.Host = client.Host
+	loc.Scheme = "http"
+	if proto := client.Header.Get("X-Forwarded-Proto"); proto != "" {
+		loc.Scheme = proto
+	} else if client.TLS != nil {
+		loc.Scheme = "https"
+	}
+
+	header.Set("Location", loc.String())
+}
+
+// stripHeaders는 지정된 헤더들을 제거
+func stripHeaders(header http.Header, names []string) {
+	for _, name := range names {
+		header.Del(name)
+	}
+}
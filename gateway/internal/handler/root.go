@@ -0,0 +1,52 @@
+package handler
+
+import (
+	_ "embed"
+	"log"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// defaultRootPage는 ROOT_PAGE=default일 때 "/"에서 제공하는 기본 안내 페이지
+//
+//go:embed static/index.html
+var defaultRootPage []byte
+
+// rootPage는 "/"에서 제공하는 정적 페이지
+type rootPage struct {
+	contentType string
+	body        []byte
+}
+
+// loadRootPage는 ROOT_PAGE 설정으로 정적 페이지 로드
+// "default"면 내장 페이지, 그 외에는 파일 경로 (시작 시 한 번 읽음)
+func loadRootPage(source string) (*rootPage, error) {
+	if source == "default" {
+		return &rootPage{contentType: "text/html; charset=utf-8", body: defaultRootPage}, nil
+	}
+
+	body, err := os.ReadFile(source)
+	if err != nil {
+		return nil, err
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(source))
+	if contentType == "" {
+		contentType = http.DetectContentType(body)
+	}
+	log.Printf("🏠 루트 페이지: %s", source)
+	return &rootPage{contentType: contentType, body: body}, nil
+}
+
+// handleRoot는 "/" 정적 페이지 응답 (GET/HEAD)
+func (h *ProxyHandler) handleRoot(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", h.rootPage.contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(h.rootPage.body)))
+	if r.Method == http.MethodHead {
+		return
+	}
+	w.Write(h.rootPage.body)
+}
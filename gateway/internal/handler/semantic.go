@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/devbrain/gateway/internal/cache"
+)
+
+// semanticTimeout는 시맨틱 캐시 조회/저장 시 임베딩 호출 최대 시간
+const semanticTimeout = 3 * time.Second
+
+// SetSemanticCache는 시맨틱 캐시 등록 (nil이면 정확 일치 캐시만 사용)
+func (h *ProxyHandler) SetSemanticCache(sc *cache.SemanticCache) {
+	h.semantic = sc
+}
+
+// getExact는 정확히 일치하는 캐시 항목만 조회 (임베딩 호출 없음, 없으면 nil)
+func (h *ProxyHandler) getExact(ctx context.Context, query string) *cache.CachedResponse {
+	if !h.cacheEnabled(ctx) || !h.cache.IsConnected() {
+		return nil
+	}
+	cached, err := h.cache.Get(cacheQuery(ctx, query))
+	if err != nil {
+		return nil
+	}
+	return cached
+}
+
+// getCached는 정확 일치 캐시를 먼저 조회하고, 없으면 시맨틱 캐시에서 유사 질문 조회
+// 반환하는 score는 정확 일치면 1.0
+func (h *ProxyHandler) getCached(ctx context.Context, query string) (*cache.CachedResponse, float64) {
+	if !h.cacheEnabled(ctx) || !h.cache.IsConnected() {
+		return nil, 0
+	}
+
+	if cached := h.getExact(ctx, query); cached != nil {
+		return cached, 1.0
+	}
+
+	// 클라이언트가 캐시 키를 지정했거나 테넌트가 있는 요청은 그 키 공간의 항목만 사용
+	if h.semantic == nil || scopedCacheKey(ctx) {
+		return nil, 0
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, semanticTimeout)
+	defer cancel()
+
+	match, err := h.semantic.FindSimilar(ctx, query)
+	if err != nil {
+		log.Printf("⚠️ 시맨틱 캐시 조회 실패: %v", err)
+		return nil, 0
+	}
+	if match == nil {
+		return nil, 0
+	}
+	// 다른 분류(다른 Backend)의 답변은 사용하지 않음
+	if h.queryRouter.classify(match.Response.Query) != h.queryRouter.classify(query) {
+		return nil, 0
+	}
+
+	log.Printf("🧠 시맨틱 캐시 히트 (%.3f): %s", match.Score, query[:min(30, len(query))])
+	return match.Response, match.Score
+}
+
+// setSimilarityHeader는 시맨틱 캐시 히트인 경우 유사도 헤더 설정
+func setSimilarityHeader(w http.ResponseWriter, score float64) {
+	if score < 1.0 {
+		w.Header().Set("X-Cache-Similarity", strconv.FormatFloat(score, 'f', 4, 64))
+	}
+}
+
+// storeSemantic은 시맨틱 캐시에 쿼리 임베딩을 비동기로 저장 (응답 경로를 지연시키지 않음)
+func (h *ProxyHandler) storeSemantic(query string, ttl time.Duration) {
+	if h.semantic == nil {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), semanticTimeout)
+		defer cancel()
+
+		if err := h.semantic.Store(ctx, query, ttl); err != nil {
+			log.Printf("⚠️ 시맨틱 캐시 저장 실패: %v", err)
+		}
+	}()
+}
@@ -0,0 +1,296 @@
+package handler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// sseWriter는 SSE 이벤트를 클라이언트로 전달하는 래퍼
+// gzip이 협상된 경우 이벤트마다 gzip.Writer를 Flush하여 실시간성을 유지한다.
+// (이벤트 단위 Flush는 압축률을 크게 떨어뜨리므로 기본값은 비활성화)
+type sseWriter struct {
+	w       io.Writer
+	gz      *gzip.Writer
+	flusher http.Flusher
+}
+
+// newSSEWriter는 SSE 헤더를 설정하고 sseWriter 생성
+// 스트리밍을 지원하지 않는 ResponseWriter이면 false 반환
+func (h *ProxyHandler) newSSEWriter(w http.ResponseWriter, r *http.Request) (*sseWriter, bool) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return nil, false
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	stripHeaders(w.Header(), h.config.StripResponseHeaders)
+
+	// 스트림은 오래 유지되므로 SERVER_WRITE_TIMEOUT 쓰기 데드라인을 해제 (최대 시간은 STREAM_MAX_DURATION으로 제한)
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		log.Printf("⚠️ SSE 쓰기 데드라인 해제 실패: %v", err)
+	}
+
+	sw := &sseWriter{w: w, flusher: flusher}
+
+	if h.config.SSEGzipEnabled && acceptsGzip(r) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Add("Vary", "Accept-Encoding")
+		sw.gz = gzip.NewWriter(w)
+		sw.w = sw.gz
+	}
+
+	return sw, true
+}
+
+// Write는 데이터를 (필요시 압축하여) 기록
+func (sw *sseWriter) Write(b []byte) (int, error) {
+	return sw.w.Write(b)
+}
+
+// Flush는 gzip 버퍼와 HTTP 버퍼를 모두 비워 클라이언트로 즉시 전송
+func (sw *sseWriter) Flush() error {
+	if sw.gz != nil {
+		if err := sw.gz.Flush(); err != nil {
+			return err
+		}
+	}
+	sw.flusher.Flush()
+	return nil
+}
+
+// send는 SSE 텍스트를 기록하고 즉시 Flush
+// 에러는 클라이언트 연결 종료로 간주하며, 호출하는 쪽은 스트리밍을 중단해야 한다.
+func (sw *sseWriter) send(text string) error {
+	if _, err := io.WriteString(sw.w, text); err != nil {
+		return err
+	}
+	return sw.Flush()
+}
+
+// Close는 gzip 스트림을 마무리
+func (sw *sseWriter) Close() error {
+	if sw.gz != nil {
+		return sw.gz.Close()
+	}
+	return nil
+}
+
+// sseEvent는 빈 줄로 구분되는 Backend SSE 이벤트 하나
+type sseEvent struct {
+	lines  []string // 원본 라인 (주석, id:, retry: 포함, 빈 줄 제외)
+	name   string   // event 필드 (없으면 빈 문자열 = message)
+	data   []string // data 필드 값 (여러 줄이면 줄바꿈으로 연결)
+	token  bool     // 응답 텍스트로 수집한 토큰 이벤트인지
+	final  bool     // 스트림 완료를 알린 이벤트인지 (SSE_DONE_MARKERS)
+	result bool     // 최종 결과로 합칠 Backend 결과 이벤트인지 (SSE_RESULT_BACKEND_EVENT)
+}
+
+// text는 data 필드들을 SSE 규격대로 줄바꿈으로 연결한 값
+func (ev *sseEvent) text() string {
+	return strings.Join(ev.data, "\n")
+}
+
+// parseSSEField는 SSE 라인을 필드 이름과 값으로 분리
+// SSE 규격대로 콜론 뒤 공백 하나만 제거하고 (토큰 사이 공백 보존), 콜론이 없으면 라인 전체가 필드 이름
+func parseSSEField(line string) (field, value string) {
+	field, value, _ = strings.Cut(line, ":")
+	return field, strings.TrimPrefix(value, " ")
+}
+
+// sseCollector는 Backend SSE 스트림을 한 줄씩 읽어 이벤트 단위로 묶고 토큰 텍스트와 완료 여부를 수집
+// 연속된 data: 라인은 빈 줄이 나올 때까지 한 이벤트로 합치며, id:/retry:/주석은 해석하지 않고 원본 라인에 보관
+// 완료 표시(SSE_DONE_MARKERS) 이후의 이벤트와 done/timeout 등 제어 이벤트는 수집하지 않는다.
+type sseCollector struct {
+	markers doneMarkers
+	pending sseEvent // 빈 줄을 아직 만나지 않은 이벤트
+	done    bool
+
+	text   strings.Builder // 누적된 전체 응답 텍스트
+	chunks []string        // 토큰 이벤트 단위 청크 (SSE_STORE_CHUNKS)
+
+	resultEvent string         // Backend 결과 이벤트 이름 (비어 있으면 수집 안 함)
+	result      map[string]any // Backend 결과 이벤트의 JSON 필드 (인용, 토큰 수 등)
+}
+
+func newSSECollector(markers doneMarkers) *sseCollector {
+	return &sseCollector{markers: markers}
+}
+
+// feed는 SSE 라인 하나를 처리하고, 빈 줄로 이벤트가 끝나면 그 이벤트와 true 반환
+func (c *sseCollector) feed(line string) (*sseEvent, bool) {
+	if line == "" {
+		return c.dispatch(), true
+	}
+
+	c.pending.lines = append(c.pending.lines, line)
+	if strings.HasPrefix(line, ":") {
+		return nil, false // 주석 (keep-alive 등)
+	}
+
+	switch field, value := parseSSEField(line); field {
+	case "event":
+		c.pending.name = strings.TrimSpace(value)
+	case "data":
+		c.pending.data = append(c.pending.data, value)
+	}
+	return nil, false
+}
+
+// flush는 빈 줄 없이 스트림이 끝난 마지막 이벤트를 처리 (없으면 false)
+// 규격상 버려야 하지만 마지막 빈 줄을 생략하는 Backend가 있으므로 완료된 이벤트로 취급
+func (c *sseCollector) flush() (*sseEvent, bool) {
+	if len(c.pending.lines) == 0 {
+		return nil, false
+	}
+	return c.dispatch(), true
+}
+
+// dispatch는 대기 중인 이벤트를 끝내고 완료 표시 확인, 토큰 이벤트면 응답에 누적
+func (c *sseCollector) dispatch() *sseEvent {
+	ev := c.pending
+	c.pending = sseEvent{}
+
+	if c.done {
+		return &ev
+	}
+	if c.markers.events[ev.name] {
+		c.done, ev.final = true, true
+		return &ev
+	}
+	for _, data := range ev.data {
+		if c.markers.data[strings.TrimSpace(data)] {
+			c.done, ev.final = true, true
+			return &ev
+		}
+	}
+
+	// Backend 결과 이벤트: JSON 객체면 최종 결과에 합치도록 보관 (JSON이 아니면 일반 이벤트로 전달)
+	if c.resultEvent != "" && ev.name == c.resultEvent {
+		var fields map[string]any
+		if err := json.Unmarshal([]byte(ev.text()), &fields); err == nil && fields != nil {
+			if c.result == nil {
+				c.result = fields
+			} else {
+				for k, v := range fields {
+					c.result[k] = v
+				}
+			}
+			ev.result = true
+		}
+		return &ev
+	}
+
+	if len(ev.data) == 0 || !isTokenEvent(ev.name) {
+		return &ev
+	}
+	ev.token = true
+	text := ev.text()
+	c.text.WriteString(text)
+	c.chunks = append(c.chunks, text)
+	return &ev
+}
+
+// formatEvent는 Backend 이벤트를 클라이언트로 전달할 SSE 텍스트로 변환 (빈 줄로 끝남)
+// SSE_WRAP_TOKENS면 토큰 이벤트의 data 라인들을 {"token": "..."} 한 줄로 합치고 나머지 라인은 원본 그대로 전달
+func (h *ProxyHandler) formatEvent(ev *sseEvent) string {
+	var b strings.Builder
+	wrap := ev.token && h.config.SSEWrapTokens
+	wrapped := false
+	for _, line := range ev.lines {
+		if field, _ := parseSSEField(line); wrap && field == "data" {
+			if !wrapped {
+				b.WriteString(wrapToken(ev.text()) + "\n")
+				wrapped = true
+			}
+			continue
+		}
+		b.WriteString(line + "\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// formatResult는 스트림 완료 시 전달할 최종 결과 이벤트 (SSE_RESULT_EVENT)
+// 전체 응답 텍스트를 RESPONSE_ANSWER_FIELD에 담고, Backend 결과 이벤트 필드와 extra를 함께 포함
+func (h *ProxyHandler) formatResult(query, text string, result, extra map[string]any) string {
+	fields := make(map[string]any, len(result)+len(extra))
+	for k, v := range result {
+		fields[k] = v
+	}
+	for k, v := range extra {
+		fields[k] = v
+	}
+	body := h.envelope(query, text, fields)
+	return "event:result\ndata:" + string(bytes.TrimSpace(body)) + "\n\n"
+}
+
+// isTokenEvent는 이벤트 이름이 토큰 데이터 이벤트인지 확인
+// 이름 없는 이벤트(기본 message)와 token 이벤트만 토큰으로 취급하고 done/timeout 등 제어 이벤트는 제외
+func isTokenEvent(event string) bool {
+	return event == "" || event == "message" || event == "token"
+}
+
+// wrapToken은 토큰 텍스트를 {"token": "..."} JSON data 라인으로 변환 (SSE_WRAP_TOKENS)
+func wrapToken(text string) string {
+	payload, _ := json.Marshal(map[string]string{"token": text})
+	return "data:" + string(payload)
+}
+
+// formatData는 텍스트를 하나의 SSE 이벤트 data 라인들로 변환 (빈 줄 제외)
+// 줄바꿈이 포함된 텍스트는 여러 data 라인으로 나눠 클라이언트가 원본 그대로 복원하도록 함
+func (h *ProxyHandler) formatData(text string) string {
+	if h.config.SSEWrapTokens {
+		return wrapToken(text) + "\n"
+	}
+
+	var b strings.Builder
+	for _, line := range strings.Split(text, "\n") {
+		b.WriteString("data:" + line + "\n")
+	}
+	return b.String()
+}
+
+// acceptsGzip는 클라이언트가 gzip 인코딩을 허용하는지 확인
+func acceptsGzip(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		encoding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if strings.EqualFold(strings.TrimSpace(encoding), "gzip") {
+			return strings.ReplaceAll(strings.TrimSpace(params), " ", "") != "q=0"
+		}
+	}
+	return false
+}
+
+// doneMarkers는 Backend 스트림의 완료 표시 집합
+// 완료 이후의 data는 캐시용 응답에 누적하지 않는다.
+type doneMarkers struct {
+	data   map[string]bool // data 값 (예: [DONE], [END])
+	events map[string]bool // event 이름 (예: done)
+}
+
+// parseDoneMarkers는 SSE_DONE_MARKERS 설정을 파싱
+// "event:" 접두사가 붙은 항목은 event 이름, 나머지는 data 값으로 취급
+func parseDoneMarkers(markers []string) doneMarkers {
+	dm := doneMarkers{
+		data:   make(map[string]bool),
+		events: make(map[string]bool),
+	}
+	for _, marker := range markers {
+		if name, ok := strings.CutPrefix(marker, "event:"); ok {
+			dm.events[strings.TrimSpace(name)] = true
+			continue
+		}
+		dm.data[marker] = true
+	}
+	return dm
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devbrain/gateway/internal/middleware"
+)
+
+// requestTimeoutHeader는 요청 하나의 Backend 타임아웃을 지정하는 헤더 (초 단위)
+const requestTimeoutHeader = "X-Request-Timeout"
+
+var errInvalidRequestTimeout = errors.New("X-Request-Timeout must be a positive number of seconds")
+
+// backendTimeout은 요청에 적용할 Backend 타임아웃 (0이면 제한 없음)
+// X-Request-Timeout 헤더가 있으면 기본값 대신 사용하며 REQUEST_TIMEOUT_MAX를 넘으면 최대값으로 제한
+// REQUEST_TIMEOUT_MAX가 0이면 헤더를 무시
+func (h *ProxyHandler) backendTimeout(r *http.Request, fallback time.Duration) (time.Duration, error) {
+	raw := strings.TrimSpace(r.Header.Get(requestTimeoutHeader))
+	if raw == "" || h.config.RequestTimeoutMax <= 0 {
+		return fallback, nil
+	}
+
+	seconds, err := strconv.ParseFloat(raw, 64)
+	if err != nil || seconds <= 0 || math.IsNaN(seconds) || math.IsInf(seconds, 0) {
+		return 0, errInvalidRequestTimeout
+	}
+
+	maxTimeout := time.Duration(h.config.RequestTimeoutMax) * time.Second
+	if seconds >= maxTimeout.Seconds() {
+		return maxTimeout, nil
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// validateRequestTimeout은 X-Request-Timeout 값을 검증하고, 잘못되었으면 400 응답을 쓰고 false 반환
+// 요청 합치기(fetchChat) 안에서 검증하면 한 클라이언트의 잘못된 값이 함께 기다리는 모든 요청을 실패시키므로
+// 채팅 핸들러는 Backend 호출 전에 먼저 확인한다.
+func (h *ProxyHandler) validateRequestTimeout(w http.ResponseWriter, r *http.Request) bool {
+	if _, err := h.backendTimeout(r, 0); err != nil {
+		middleware.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return false
+	}
+	return true
+}
+
+// backendContext는 Backend 타임아웃을 적용한 요청 컨텍스트 생성
+// X-Request-Timeout 값이 잘못되었으면 400 응답을 쓰고 false 반환
+func (h *ProxyHandler) backendContext(w http.ResponseWriter, r *http.Request, fallback time.Duration) (context.Context, context.CancelFunc, bool) {
+	timeout, err := h.backendTimeout(r, fallback)
+	if err != nil {
+		middleware.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return nil, nil, false
+	}
+	if timeout <= 0 {
+		ctx, cancel := context.WithCancel(r.Context())
+		return ctx, cancel, true
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	return ctx, cancel, true
+}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/devbrain/gateway/internal/cache"
+)
+
+// 답변 추출 실패 사유 (캐시 저장 여부 로그용)
+var (
+	errAnswerNotJSON = errors.New("response is not JSON")
+	errAnswerMissing = errors.New("answer field not found")
+	errAnswerEmpty   = errors.New("answer is empty")
+)
+
+// extractAnswer는 Backend 응답 JSON에서 RESPONSE_ANSWER_PATH 위치의 답변 문자열 추출
+// 경로는 점(.)으로 구분하며 숫자 세그먼트는 배열 인덱스로 해석 (예: "data.choices.0.text")
+func (h *ProxyHandler) extractAnswer(body []byte) (string, error) {
+	var node any
+	if err := json.Unmarshal(body, &node); err != nil {
+		return "", errAnswerNotJSON
+	}
+
+	for _, segment := range strings.Split(h.config.ResponseAnswerPath, ".") {
+		switch v := node.(type) {
+		case map[string]any:
+			node = v[segment]
+		case []any:
+			i, err := strconv.Atoi(segment)
+			if err != nil || i < 0 || i >= len(v) {
+				return "", errAnswerMissing
+			}
+			node = v[i]
+		default:
+			return "", errAnswerMissing
+		}
+	}
+
+	answer, ok := node.(string)
+	if !ok {
+		return "", errAnswerMissing
+	}
+	if strings.TrimSpace(answer) == "" {
+		return "", errAnswerEmpty
+	}
+	return answer, nil
+}
+
+// resolveAnswer는 답변을 추출하고, 빈 답변이면 EMPTY_RESPONSE_FALLBACK 메시지로 대체
+// 대체한 경우 empty가 true
+func (h *ProxyHandler) resolveAnswer(body []byte) (answer string, empty bool, err error) {
+	answer, err = h.extractAnswer(body)
+	if err == errAnswerEmpty && h.config.EmptyResponseFallback != "" {
+		return h.config.EmptyResponseFallback, true, nil
+	}
+	return answer, false, err
+}
+
+// cachedSyncBody는 동기 채팅 캐시 히트 응답 바디 생성
+// 캐시 시각과 경과 시간을 포함 (200이 아닌 네거티브 항목은 저장된 Backend 바디 그대로)
+func (h *ProxyHandler) cachedSyncBody(query string, cached *cache.CachedResponse) []byte {
+	if cached.StatusCode() != http.StatusOK {
+		return []byte(cached.Response)
+	}
+	return h.envelope(query, cached.Response, map[string]any{
+		"cached":      true,
+		"cached_at":   cached.CreatedAt,
+		"age_seconds": cacheAge(cached),
+	})
+}
+
+// envelope은 답변을 RESPONSE_ANSWER_FIELD 필드에 담은 일관된 응답 바디 생성
+func (h *ProxyHandler) envelope(query, answer string, extra map[string]any) []byte {
+	fields := map[string]any{
+		"query": query,
+	}
+	for k, v := range extra {
+		fields[k] = v
+	}
+	fields[h.config.ResponseAnswerField] = answer
+
+	body, _ := json.Marshal(fields)
+	return append(body, '\n')
+}
+
+// bufferedResponse는 Backend 응답을 클라이언트에 쓰지 않고 모두 버퍼링하는 ResponseWriter
+// 미스 응답을 변환하거나 합쳐진 요청들이 같은 응답을 공유하기 위해 사용
+type bufferedResponse struct {
+	header     http.Header
+	statusCode int
+	body       bytes.Buffer
+
+	// hit은 Backend 대신 다른 인스턴스가 저장한 캐시로 응답한 경우의 항목
+	hit *cache.CachedResponse
+}
+
+func newBufferedResponse() *bufferedResponse {
+	return &bufferedResponse{header: make(http.Header), statusCode: http.StatusOK}
+}
+
+func (b *bufferedResponse) Header() http.Header {
+	return b.header
+}
+
+func (b *bufferedResponse) WriteHeader(code int) {
+	b.statusCode = code
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	return b.body.Write(p)
+}
+
+// writeTo는 버퍼링된 응답을 그대로 클라이언트에 전송 (body가 nil이 아니면 대체 바디 사용)
+func (b *bufferedResponse) writeTo(w http.ResponseWriter, body []byte) {
+	// 여러 호출자가 같은 응답을 공유할 수 있으므로 헤더 값은 복사
+	for k, values := range b.header {
+		w.Header()[k] = append([]string(nil), values...)
+	}
+	if body == nil {
+		body = b.body.Bytes()
+	} else {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Del("Content-Encoding")
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(b.statusCode)
+	w.Write(body)
+}
+
+// missBody는 캐시 미스 응답을 히트와 같은 형태로 재구성
+func (h *ProxyHandler) missBody(query, answer string) []byte {
+	return h.envelope(query, answer, map[string]any{
+		"cached": false,
+	})
+}
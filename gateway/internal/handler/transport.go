@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/devbrain/gateway/internal/config"
+)
+
+// newBackendTLSConfig는 Backend 연결용 TLS 설정 생성 (관련 설정이 없으면 nil = 기본 TLS)
+// BACKEND_TLS_CLIENT_CERT/KEY는 mTLS 클라이언트 인증서, BACKEND_TLS_CA는 Backend 서버 인증서 검증용 CA (PEM)
+func newBackendTLSConfig(cfg *config.Config) (*tls.Config, error) {
+	if cfg.BackendTLSClientCert == "" && cfg.BackendTLSClientKey == "" && cfg.BackendTLSCA == "" {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if cfg.BackendTLSClientCert != "" || cfg.BackendTLSClientKey != "" {
+		if cfg.BackendTLSClientCert == "" || cfg.BackendTLSClientKey == "" {
+			return nil, errors.New("BACKEND_TLS_CLIENT_CERT and BACKEND_TLS_CLIENT_KEY must be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(cfg.BackendTLSClientCert, cfg.BackendTLSClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if cfg.BackendTLSCA != "" {
+		pem, err := os.ReadFile(cfg.BackendTLSCA)
+		if err != nil {
+			return nil, fmt.Errorf("read CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no PEM certificates in %s", cfg.BackendTLSCA)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return tlsConfig, nil
+}
+
+// newBackendTransport는 리버스 프록시와 직접 호출(스트리밍, 헬스체크 등)이 공유하는 Transport 생성
+// 기본 Transport 설정(프록시 환경 변수, 커넥션 풀 등)을 그대로 복사하고 TLS 설정만 적용
+func newBackendTransport(tlsConfig *tls.Config) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if tlsConfig != nil {
+		transport.TLSClientConfig = tlsConfig
+	}
+	return transport
+}
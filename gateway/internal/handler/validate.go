@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/devbrain/gateway/internal/middleware"
+)
+
+// fieldError는 요청 바디 필드 하나의 검증 오류
+type fieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// paramRule은 선택적 생성 파라미터의 타입/범위 규칙
+type paramRule struct {
+	integer  bool
+	min, max float64
+}
+
+// chatParamRules는 채팅 요청에 올 수 있는 선택적 파라미터 (있을 때만 검증)
+var chatParamRules = map[string]paramRule{
+	"temperature": {min: 0, max: 2},
+	"top_p":       {min: 0, max: 1},
+	"top_k":       {integer: true, min: 1, max: math.MaxInt32},
+	"max_tokens":  {integer: true, min: 1, max: math.MaxInt32},
+}
+
+// check는 값이 규칙에 맞는지 확인하고 아니면 오류 메시지 반환
+func (rule paramRule) check(raw json.RawMessage) string {
+	var value float64
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return "must be a number"
+	}
+	if rule.integer && value != math.Trunc(value) {
+		return "must be an integer"
+	}
+	if value < rule.min || value > rule.max {
+		if rule.max == math.MaxInt32 {
+			return fmt.Sprintf("must be >= %g", rule.min)
+		}
+		return fmt.Sprintf("must be between %g and %g", rule.min, rule.max)
+	}
+	return ""
+}
+
+// decodeObject는 바디를 최상위 필드 맵으로 파싱 (JSON 객체가 아니면 오류)
+func decodeObject(body []byte) (map[string]json.RawMessage, []fieldError) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil || fields == nil {
+		return nil, []fieldError{{Field: "", Message: "body must be a JSON object"}}
+	}
+	return fields, nil
+}
+
+// checkNonEmptyString은 값이 비어 있지 않은 문자열인지 확인
+func checkNonEmptyString(raw json.RawMessage) string {
+	var value string
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return "must be a string"
+	}
+	if strings.TrimSpace(value) == "" {
+		return "must not be empty"
+	}
+	return ""
+}
+
+// unknownFields는 CHAT_VALIDATION_STRICT일 때 허용되지 않은 최상위 필드 오류 목록 반환
+func (h *ProxyHandler) unknownFields(fields map[string]json.RawMessage, allowed func(string) bool) []fieldError {
+	if !h.config.ChatValidationStrict {
+		return nil
+	}
+
+	var errs []fieldError
+	for name := range fields {
+		if !allowed(name) {
+			errs = append(errs, fieldError{Field: name, Message: "unknown field"})
+		}
+	}
+	return errs
+}
+
+// validateChatBody는 POST /api/chat 요청 바디 검증
+// 쿼리(QUERY_JSON_FIELD)는 비어 있지 않은 문자열이어야 하고, 선택적 파라미터는 있을 때만 타입/범위를 확인
+func (h *ProxyHandler) validateChatBody(body []byte) []fieldError {
+	fields, errs := decodeObject(body)
+	if errs != nil {
+		return errs
+	}
+
+	queryField := h.config.QueryJSONField
+	if raw, ok := fields[queryField]; !ok {
+		errs = append(errs, fieldError{Field: queryField, Message: "required"})
+	} else if msg := checkNonEmptyString(raw); msg != "" {
+		errs = append(errs, fieldError{Field: queryField, Message: msg})
+	}
+
+	for name, rule := range chatParamRules {
+		if raw, ok := fields[name]; ok {
+			if msg := rule.check(raw); msg != "" {
+				errs = append(errs, fieldError{Field: name, Message: msg})
+			}
+		}
+	}
+
+	errs = append(errs, h.unknownFields(fields, func(name string) bool {
+		_, known := chatParamRules[name]
+		return name == queryField || known
+	})...)
+	return errs
+}
+
+// validateBatchBody는 POST /api/chat/batch 요청 바디 검증 (각 질문은 비어 있지 않은 문자열)
+func (h *ProxyHandler) validateBatchBody(body []byte) []fieldError {
+	fields, errs := decodeObject(body)
+	if errs != nil {
+		return errs
+	}
+
+	var queries []json.RawMessage
+	if raw, ok := fields["queries"]; !ok {
+		errs = append(errs, fieldError{Field: "queries", Message: "required"})
+	} else if err := json.Unmarshal(raw, &queries); err != nil {
+		errs = append(errs, fieldError{Field: "queries", Message: "must be an array of strings"})
+	}
+	for i, raw := range queries {
+		if msg := checkNonEmptyString(raw); msg != "" {
+			errs = append(errs, fieldError{Field: fmt.Sprintf("queries[%d]", i), Message: msg})
+		}
+	}
+
+	errs = append(errs, h.unknownFields(fields, func(name string) bool {
+		return name == "queries"
+	})...)
+	return errs
+}
+
+// writeValidationError는 필드별 오류 목록과 함께 422 응답
+func writeValidationError(w http.ResponseWriter, r *http.Request, errs []fieldError) {
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Field < errs[j].Field })
+
+	middleware.WriteErrorDetails(w, r, http.StatusUnprocessableEntity, "Unprocessable Entity",
+		"요청 바디가 올바르지 않습니다.", map[string]any{"fields": errs})
+}
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// identityContextKey는 요청 컨텍스트에 Identity를 저장하기 위한 키
+type identityContextKey struct{}
+
+// Identity는 요청을 보낸 클라이언트의 식별 정보
+type Identity struct {
+	APIKey        string // 인증된 경우 API 키
+	Authenticated bool
+}
+
+// IdentityFromContext는 AuthMiddleware가 저장한 Identity 반환 (없으면 익명)
+func IdentityFromContext(ctx context.Context) Identity {
+	identity, _ := ctx.Value(identityContextKey{}).(Identity)
+	return identity
+}
+
+// Auth는 API 키 기반 클라이언트 식별 미들웨어
+// 키가 없으면 익명으로 통과시키고, 잘못된 키는 401로 거부한다.
+type Auth struct {
+	header string
+	keys   [][]byte
+}
+
+// NewAuth는 새로운 Auth 미들웨어 생성
+// header: API 키를 담는 요청 헤더 (Authorization이면 "Bearer <key>" 형식)
+// keys: 유효한 API 키 목록
+func NewAuth(header string, keys []string) *Auth {
+	auth := &Auth{header: header}
+	for _, key := range keys {
+		auth.keys = append(auth.keys, []byte(key))
+	}
+	return auth
+}
+
+// Identify는 요청의 API 키가 유효하면 인증된 Identity 반환 (키가 없거나 잘못되면 익명)
+// Auth 바깥의 Rate Limiter가 인증 전에 한도 등급을 정할 때 사용 (RateLimiter.SetIdentifier)
+func (a *Auth) Identify(r *http.Request) Identity {
+	key := a.extractKey(r)
+	if key == "" || len(a.keys) == 0 || !a.valid(key) {
+		return Identity{}
+	}
+	return Identity{APIKey: key, Authenticated: true}
+}
+
+// Middleware는 요청에서 Identity를 확인해 컨텍스트에 저장
+func (a *Auth) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := a.extractKey(r)
+		if key == "" || len(a.keys) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !a.valid(key) {
+			log.Printf("⚠️ 잘못된 API 키: %s", r.RemoteAddr)
+			WriteError(w, r, http.StatusUnauthorized, "유효하지 않은 API 키입니다.")
+			return
+		}
+
+		identity := Identity{APIKey: key, Authenticated: true}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), identityContextKey{}, identity)))
+	})
+}
+
+// extractKey는 API 키 헤더에서 키 추출
+// API_KEY_HEADER가 Authorization일 때만 Bearer 토큰을 키로 보며, 그 외에는 Authorization을 건드리지 않아
+// 클라이언트가 보낸 Backend/OAuth 토큰이 그대로 전달된다.
+func (a *Auth) extractKey(r *http.Request) string {
+	value := r.Header.Get(a.header)
+	if !strings.EqualFold(a.header, "Authorization") {
+		return value
+	}
+	if token, ok := strings.CutPrefix(value, "Bearer "); ok {
+		return strings.TrimSpace(token)
+	}
+	return ""
+}
+
+// valid는 키가 등록된 API 키인지 상수 시간 비교로 확인
+func (a *Auth) valid(key string) bool {
+	for _, k := range a.keys {
+		if subtle.ConstantTimeCompare([]byte(key), k) == 1 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ParseCIDRs는 CIDR 또는 단일 IP 목록을 파싱 (단일 IP는 /32, /128로 취급)
+func ParseCIDRs(list []string) ([]*net.IPNet, error) {
+	var nets []*net.IPNet
+	for _, item := range list {
+		if !strings.Contains(item, "/") {
+			ip := net.ParseIP(item)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid IP: %q", item)
+			}
+			bits := 128
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, ipNet, err := net.ParseCIDR(item)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR: %q", item)
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets, nil
+}
+
+// containsIP는 IP가 목록의 네트워크 중 하나에 포함되는지 확인
+func containsIP(nets []*net.IPNet, ip net.IP) bool {
+	for _, ipNet := range nets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIPResolver는 신뢰할 수 있는 프록시를 고려해 실제 클라이언트 IP를 결정
+// 직접 연결한 주소가 신뢰하는 프록시일 때만 X-Forwarded-For를 사용하므로 헤더 위조를 막는다.
+type ClientIPResolver struct {
+	trusted []*net.IPNet
+}
+
+// NewClientIPResolver는 새로운 ClientIPResolver 생성
+// trusted가 비어 있으면 X-Forwarded-For를 무시하고 항상 직접 연결한 주소 사용
+func NewClientIPResolver(trusted []*net.IPNet) *ClientIPResolver {
+	return &ClientIPResolver{trusted: trusted}
+}
+
+// ClientIP는 요청의 실제 클라이언트 IP 반환 (파싱 실패 시 nil)
+// X-Forwarded-For를 오른쪽(가까운 프록시)부터 따라가며 신뢰하지 않는 첫 주소를 클라이언트로 본다.
+func (c *ClientIPResolver) ClientIP(r *http.Request) net.IP {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !containsIP(c.trusted, ip) {
+		return ip
+	}
+
+	hops := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := net.ParseIP(strings.TrimSpace(hops[i]))
+		if hop == nil {
+			break
+		}
+		ip = hop
+		if !containsIP(c.trusted, hop) {
+			break
+		}
+	}
+	return ip
+}
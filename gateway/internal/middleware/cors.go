@@ -0,0 +1,167 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// CORSRule은 경로 접두사별 CORS 정책
+// AllowedOrigins가 비어 있으면 교차 출처 요청을 허용하지 않음 (same-origin 전용)
+type CORSRule struct {
+	PathPrefix     string
+	AllowedOrigins []string // "*"이면 모든 출처 허용
+	AllowedMethods []string
+	AllowedHeaders []string
+}
+
+// DefaultCORSRule은 기존 전역 CORS 정책 (모든 출처 허용)
+var DefaultCORSRule = CORSRule{
+	AllowedOrigins: []string{"*"},
+	AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+	AllowedHeaders: []string{"Content-Type", "Authorization"},
+}
+
+// CORS는 경로 접두사별 규칙을 적용하는 CORS 미들웨어
+type CORS struct {
+	rules       []CORSRule // 긴 접두사 우선으로 정렬
+	defaultRule CORSRule
+
+	// hasRoute가 설정되면 존재하는 라우트의 유효한 preflight만 응답하고
+	// 나머지 OPTIONS 요청은 일반 라우팅(404/405 등)으로 넘긴다.
+	hasRoute func(path string) bool
+}
+
+// NewCORS는 새로운 CORS 미들웨어 생성
+// 어떤 규칙에도 해당하지 않는 경로는 defaultRule 적용
+func NewCORS(defaultRule CORSRule, rules []CORSRule) *CORS {
+	sorted := append([]CORSRule(nil), rules...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return len(sorted[i].PathPrefix) > len(sorted[j].PathPrefix)
+	})
+	return &CORS{rules: sorted, defaultRule: defaultRule}
+}
+
+// CORSMiddleware는 기본 정책만 사용하는 CORS 미들웨어
+func CORSMiddleware(next http.Handler) http.Handler {
+	return NewCORS(DefaultCORSRule, nil).Middleware(next)
+}
+
+// SetRouteMatcher는 preflight 응답 대상 라우트를 판별하는 함수 설정
+// 설정하지 않으면 모든 OPTIONS 요청에 200으로 응답 (기존 동작)
+func (c *CORS) SetRouteMatcher(hasRoute func(path string) bool) {
+	c.hasRoute = hasRoute
+}
+
+// ruleFor는 경로에 해당하는 규칙 반환
+func (c *CORS) ruleFor(path string) CORSRule {
+	for _, rule := range c.rules {
+		if strings.HasPrefix(path, rule.PathPrefix) {
+			return rule
+		}
+	}
+	return c.defaultRule
+}
+
+// Middleware는 CORS 헤더를 추가하는 미들웨어
+func (c *CORS) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rule := c.ruleFor(r.URL.Path)
+
+		// CORS 헤더 설정 (허용된 출처인 경우만)
+		origin, allowed := rule.allowOrigin(r.Header.Get("Origin"))
+		if allowed {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Allow-Methods", strings.Join(rule.AllowedMethods, ", "))
+			w.Header().Set("Access-Control-Allow-Headers", strings.Join(rule.AllowedHeaders, ", "))
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+			if origin != "*" {
+				w.Header().Add("Vary", "Origin")
+			}
+		}
+
+		// Preflight 요청 처리
+		if r.Method == http.MethodOptions && (c.hasRoute == nil || c.isPreflight(r, rule, allowed)) {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isPreflight는 CORS가 허용된 라우트에 대한 유효한 preflight 요청인지 확인
+// (존재하는 라우트 + 허용된 출처 + 허용된 Access-Control-Request-Method)
+func (c *CORS) isPreflight(r *http.Request, rule CORSRule, originAllowed bool) bool {
+	method := r.Header.Get("Access-Control-Request-Method")
+	if !originAllowed || method == "" || !c.hasRoute(r.URL.Path) {
+		return false
+	}
+	for _, allowed := range rule.AllowedMethods {
+		if strings.EqualFold(allowed, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowOrigin은 요청 출처에 대해 응답할 Access-Control-Allow-Origin 값 반환
+func (rule CORSRule) allowOrigin(origin string) (string, bool) {
+	for _, allowed := range rule.AllowedOrigins {
+		if allowed == "*" {
+			return "*", true
+		}
+		if origin != "" && strings.EqualFold(allowed, origin) {
+			return origin, true
+		}
+	}
+	return "", false
+}
+
+// ParseCORSRules는 CORS_PATH_RULES 설정을 파싱
+// 형식: "접두사|출처들|메서드들|헤더들;..." (각 목록은 쉼표 구분)
+// 메서드/헤더가 비어 있으면 defaultRule 값을 사용하고, 출처가 비어 있으면 same-origin 전용
+func ParseCORSRules(raw string, defaultRule CORSRule) ([]CORSRule, error) {
+	var rules []CORSRule
+	for _, entry := range strings.Split(raw, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		fields := strings.Split(entry, "|")
+		if len(fields) < 2 || len(fields) > 4 || strings.TrimSpace(fields[0]) == "" {
+			return nil, fmt.Errorf("invalid CORS rule: %q", entry)
+		}
+		for len(fields) < 4 {
+			fields = append(fields, "")
+		}
+
+		rule := CORSRule{
+			PathPrefix:     strings.TrimSpace(fields[0]),
+			AllowedOrigins: splitList(fields[1]),
+			AllowedMethods: splitList(fields[2]),
+			AllowedHeaders: splitList(fields[3]),
+		}
+		if len(rule.AllowedMethods) == 0 {
+			rule.AllowedMethods = defaultRule.AllowedMethods
+		}
+		if len(rule.AllowedHeaders) == 0 {
+			rule.AllowedHeaders = defaultRule.AllowedHeaders
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// splitList는 쉼표 구분 문자열을 목록으로 변환 (빈 항목 제외)
+func splitList(raw string) []string {
+	var list []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// defaultErrorTemplate은 브라우저 요청(Accept: text/html)에 사용하는 기본 에러 페이지
+const defaultErrorTemplate = `<!DOCTYPE html>
+<html lang="ko">
+<head>
+  <meta charset="utf-8">
+  <title>{{.Status}} {{.Error}}</title>
+  <style>
+    body { font-family: -apple-system, BlinkMacSystemFont, sans-serif; max-width: 640px; margin: 64px auto; padding: 0 16px; color: #222; }
+    h1 { font-size: 1.5em; }
+    p { color: #555; }
+  </style>
+</head>
+<body>
+  <h1>{{.Status}} {{.Error}}</h1>
+  {{if .Message}}<p>{{.Message}}</p>{{end}}
+  <p><a href="/health">/health</a> · <a href="/version">/version</a></p>
+</body>
+</html>
+`
+
+// errorTemplate은 HTML 에러 페이지 템플릿 (LoadErrorTemplate으로 교체 가능)
+var errorTemplate = template.Must(template.New("error").Parse(defaultErrorTemplate))
+
+// errorPage는 에러 템플릿에 전달하는 값
+type errorPage struct {
+	Status  int
+	Error   string
+	Message string
+}
+
+// LoadErrorTemplate은 HTML 에러 페이지 템플릿 파일 로드 (시작 시 한 번 호출)
+// 템플릿에서는 {{.Status}}, {{.Error}}, {{.Message}}를 사용할 수 있다.
+func LoadErrorTemplate(path string) error {
+	tmpl, err := template.ParseFiles(path)
+	if err != nil {
+		return err
+	}
+	errorTemplate = tmpl
+	return nil
+}
+
+// WriteError는 에러 응답 작성 (error 필드는 상태 코드의 표준 문구)
+// Accept 헤더가 text/html을 선호하면 HTML 에러 페이지, 아니면 JSON
+func WriteError(w http.ResponseWriter, r *http.Request, status int, message string) {
+	WriteErrorDetails(w, r, status, http.StatusText(status), message, nil)
+}
+
+// WriteErrorDetails는 error 이름과 JSON 전용 추가 필드(검증 오류 목록 등)를 지정하는 WriteError
+func WriteErrorDetails(w http.ResponseWriter, r *http.Request, status int, name, message string, details map[string]any) {
+	if prefersHTML(r) {
+		var buf bytes.Buffer
+		if err := errorTemplate.Execute(&buf, errorPage{Status: status, Error: name, Message: message}); err == nil {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Header().Set("X-Content-Type-Options", "nosniff")
+			w.WriteHeader(status)
+			w.Write(buf.Bytes())
+			return
+		}
+		// 템플릿 실행 실패 시 JSON으로 응답
+	}
+
+	fields := map[string]any{"error": name}
+	if message != "" {
+		fields["message"] = message
+	}
+	for k, v := range details {
+		fields[k] = v
+	}
+	body, _ := json.Marshal(fields)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	w.Write(append(body, '\n'))
+}
+
+// prefersHTML은 Accept 헤더가 JSON보다 HTML을 선호하는지 확인
+// text/html(또는 text/*)이 명시된 경우만 HTML로 보며, */*만 있는 API 클라이언트(curl 등)는 JSON
+func prefersHTML(r *http.Request) bool {
+	if r == nil {
+		return false
+	}
+
+	var htmlQ, jsonQ float64
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, q := parseMediaRange(part)
+		switch mediaType {
+		case "text/html", "text/*":
+			htmlQ = max(htmlQ, q)
+		case "application/json", "application/*", "*/*":
+			jsonQ = max(jsonQ, q)
+		}
+	}
+	return htmlQ > 0 && htmlQ > jsonQ
+}
+
+// parseMediaRange는 Accept 항목 하나에서 미디어 타입과 q 값 추출 (q 생략 시 1)
+func parseMediaRange(part string) (string, float64) {
+	params := strings.Split(part, ";")
+	mediaType := strings.ToLower(strings.TrimSpace(params[0]))
+
+	q := 1.0
+	for _, param := range params[1:] {
+		name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if ok && strings.TrimSpace(name) == "q" {
+			if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				q = parsed
+			}
+		}
+	}
+	return mediaType, q
+}
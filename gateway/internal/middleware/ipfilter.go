@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"log"
+	"net"
+	"net/http"
+)
+
+// IPFilter는 클라이언트 IP의 CIDR 허용/차단 목록 미들웨어
+// 판정 순서: 차단 목록 → 허용 목록 → 기본 정책
+type IPFilter struct {
+	allow        []*net.IPNet
+	deny         []*net.IPNet
+	defaultAllow bool
+	resolver     *ClientIPResolver
+}
+
+// NewIPFilter는 새로운 IPFilter 생성 (CIDR은 시작 시 한 번만 파싱해서 전달)
+// defaultAllow는 어느 목록에도 해당하지 않는 IP의 처리
+func NewIPFilter(allow, deny []*net.IPNet, defaultAllow bool, resolver *ClientIPResolver) *IPFilter {
+	return &IPFilter{
+		allow:        allow,
+		deny:         deny,
+		defaultAllow: defaultAllow,
+		resolver:     resolver,
+	}
+}
+
+// Allowed는 IP의 접근 허용 여부 판정
+func (f *IPFilter) Allowed(ip net.IP) bool {
+	if ip == nil {
+		return f.defaultAllow
+	}
+	if containsIP(f.deny, ip) {
+		return false
+	}
+	if containsIP(f.allow, ip) {
+		return true
+	}
+	return f.defaultAllow
+}
+
+// Middleware는 허용되지 않은 클라이언트를 403으로 거부하는 미들웨어
+func (f *IPFilter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip := f.resolver.ClientIP(r)
+		if !f.Allowed(ip) {
+			log.Printf("🚫 IP 차단: %s %s %s", ip, r.Method, r.URL.Path)
+			WriteError(w, r, http.StatusForbidden, "허용되지 않은 네트워크입니다.")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
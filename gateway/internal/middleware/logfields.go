@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// logFieldsContextKey는 요청 컨텍스트에 LogFields를 저장하기 위한 키
+type logFieldsContextKey struct{}
+
+// LogFields는 요청 처리 중 핸들러가 추가한 접근 로그 필드
+// AccessLogger가 요청 시작 시 컨텍스트에 넣고, 응답 후 한 줄로 함께 기록한다.
+type LogFields struct {
+	mu     sync.Mutex
+	keys   []string // 추가된 순서 유지
+	values map[string]any
+}
+
+// withLogFields는 빈 LogFields를 담은 요청 반환
+func withLogFields(r *http.Request) (*http.Request, *LogFields) {
+	fields := &LogFields{values: make(map[string]any)}
+	return r.WithContext(context.WithValue(r.Context(), logFieldsContextKey{}, fields)), fields
+}
+
+// AddLogField는 현재 요청의 접근 로그에 key=value 필드 추가 (같은 key는 덮어씀)
+// AccessLogger를 거치지 않은 요청이면 아무 것도 하지 않음
+func AddLogField(ctx context.Context, key string, value any) {
+	fields, ok := ctx.Value(logFieldsContextKey{}).(*LogFields)
+	if !ok {
+		return
+	}
+
+	fields.mu.Lock()
+	defer fields.mu.Unlock()
+	if _, exists := fields.values[key]; !exists {
+		fields.keys = append(fields.keys, key)
+	}
+	fields.values[key] = value
+}
+
+// String은 "key=value key=value" 형식으로 필드 반환 (없으면 빈 문자열)
+func (f *LogFields) String() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	parts := make([]string, 0, len(f.keys))
+	for _, key := range f.keys {
+		parts = append(parts, fmt.Sprintf("%s=%v", key, f.values[key]))
+	}
+	return strings.Join(parts, " ")
+}
@@ -1,15 +1,31 @@
 package middleware
 
 import (
+	"fmt"
+	"io"
 	"log"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 )
 
-// responseWriter는 상태 코드를 캡처하기 위한 래퍼
+// 접근 로그 형식 (LOG_FORMAT)
+const (
+	LogFormatText     = "text"     // 기본: [METHOD] path addr - status (duration) key=value...
+	LogFormatCombined = "combined" // Apache/NGINX Combined Log Format
+)
+
+// combinedTimeLayout은 Combined Log Format의 시각 형식 ([10/Oct/2000:13:55:36 -0700])
+const combinedTimeLayout = "02/Jan/2006:15:04:05 -0700"
+
+// responseWriter는 상태 코드와 응답 바이트 수를 캡처하기 위한 래퍼
 type responseWriter struct {
 	http.ResponseWriter
 	statusCode int
+	bytes      int64
+	capture    io.Writer // LOG_SAMPLE_RATE 상세 로그 대상이면 응답 바디 복사본 (nil이면 생략)
 }
 
 func (rw *responseWriter) WriteHeader(code int) {
@@ -17,9 +33,106 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
-// LoggingMiddleware는 요청/응답 로깅 미들웨어
+func (rw *responseWriter) Write(b []byte) (int, error) {
+	n, err := rw.ResponseWriter.Write(b)
+	rw.bytes += int64(n)
+	if rw.capture != nil {
+		rw.capture.Write(b[:n])
+	}
+	return n, err
+}
+
+// Flush는 SSE 스트리밍을 위해 원본 ResponseWriter의 Flush 호출 (지원하지 않으면 무시)
+func (rw *responseWriter) Flush() {
+	if flusher, ok := rw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap은 http.ResponseController가 원본 ResponseWriter에 접근하도록 반환 (쓰기 데드라인 조정 등)
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// AccessLogger는 요청/응답 접근 로그를 기록
+// 애플리케이션 로그와 분리된 Writer(파일 등)를 사용할 수 있다.
+type AccessLogger struct {
+	logger *log.Logger
+	format string // LogFormatText | LogFormatCombined
+
+	// 헤더/바디를 포함한 상세 로그를 남길 요청 샘플링 (nil이면 사용 안 함)
+	sampler *logSampler
+
+	// 접근 로그에서 제외할 경로 (헬스 체크 등)
+	excludeExact  map[string]bool
+	excludePrefix []string
+}
+
+// NewAccessLogger는 새로운 AccessLogger 생성
+// logger가 nil이면 기본 애플리케이션 로거 사용
+func NewAccessLogger(logger *log.Logger) *AccessLogger {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &AccessLogger{logger: logger, format: LogFormatText}
+}
+
+// SetFormat은 접근 로그 형식 설정 (비어 있으면 text)
+// combined는 로그 수집 도구가 그대로 파싱할 수 있도록 로거의 시각 접두사 없이 한 줄씩 기록한다.
+func (al *AccessLogger) SetFormat(format string) error {
+	switch format {
+	case "", LogFormatText:
+		al.format = LogFormatText
+	case LogFormatCombined:
+		al.format = LogFormatCombined
+		al.logger = log.New(al.logger.Writer(), "", 0)
+	default:
+		return fmt.Errorf("unknown log format %q (text, combined)", format)
+	}
+	return nil
+}
+
+// SetExcludePaths는 접근 로그에서 제외할 경로 패턴 설정
+// "/health"처럼 쓰면 정확히 일치, "/metrics*"처럼 *로 끝나면 접두사 일치
+func (al *AccessLogger) SetExcludePaths(patterns []string) {
+	al.excludeExact = make(map[string]bool)
+	al.excludePrefix = nil
+	for _, pattern := range patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			al.excludePrefix = append(al.excludePrefix, prefix)
+			continue
+		}
+		al.excludeExact[pattern] = true
+	}
+}
+
+// excluded는 경로가 접근 로그 제외 대상인지 확인
+func (al *AccessLogger) excluded(path string) bool {
+	if al.excludeExact[path] {
+		return true
+	}
+	for _, prefix := range al.excludePrefix {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// LoggingMiddleware는 기본 로거를 사용하는 요청/응답 로깅 미들웨어
 func LoggingMiddleware(next http.Handler) http.Handler {
+	return NewAccessLogger(nil).Middleware(next)
+}
+
+// Middleware는 요청/응답 로깅 미들웨어
+func (al *AccessLogger) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 제외 대상은 로그 없이 그대로 처리
+		if al.excluded(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
 		start := time.Now()
 
 		// 응답 래퍼 생성
@@ -28,39 +141,71 @@ func LoggingMiddleware(next http.Handler) http.Handler {
 			statusCode:     http.StatusOK,
 		}
 
+		// 핸들러가 AddLogField로 필드를 추가할 수 있도록 컨텍스트에 저장
+		r, fields := withLogFields(r)
+
+		// 샘플링된 요청은 요청/응답 바디를 캡처
+		var sample *sampledRequest
+		if al.sampler.sampled() {
+			sample = al.sampler.start(r, rw)
+		}
+
 		// 다음 핸들러 실행
 		next.ServeHTTP(rw, r)
 
-		// 로깅
+		// 상세 로그는 메타데이터 한 줄 뒤에 기록
+		if sample != nil {
+			defer al.logSample(r, rw, sample)
+		}
+
+		if al.format == LogFormatCombined {
+			al.logger.Println(combinedLine(r, rw, start))
+			return
+		}
+
+		// 로깅 (핸들러가 추가한 필드는 끝에 key=value로 덧붙임)
 		duration := time.Since(start)
-		log.Printf("[%s] %s %s - %d (%v)",
+		line := fmt.Sprintf("[%s] %s %s - %d (%v)",
 			r.Method,
 			r.URL.Path,
 			r.RemoteAddr,
 			rw.statusCode,
 			duration,
 		)
-	})
-}
-
-// CORSMiddleware는 CORS 헤더를 추가하는 미들웨어
-func CORSMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// CORS 헤더 설정
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-		w.Header().Set("Access-Control-Allow-Credentials", "true")
-
-		// Preflight 요청 처리
-		if r.Method == http.MethodOptions {
-			w.WriteHeader(http.StatusOK)
-			return
+		if extra := fields.String(); extra != "" {
+			line += " " + extra
 		}
-
-		next.ServeHTTP(w, r)
+		al.logger.Println(line)
 	})
 }
 
+// combinedLine은 Combined Log Format 한 줄 생성
+// host - - [time] "request line" status bytes "referer" "user-agent" (AddLogField 필드는 포함하지 않음)
+func combinedLine(r *http.Request, rw *responseWriter, start time.Time) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	bytes := "-"
+	if rw.bytes > 0 {
+		bytes = strconv.FormatInt(rw.bytes, 10)
+	}
 
+	return fmt.Sprintf("%s - - [%s] %s %d %s %s %s",
+		host,
+		start.Format(combinedTimeLayout),
+		quoteLogField(r.Method+" "+r.RequestURI+" "+r.Proto),
+		rw.statusCode,
+		bytes,
+		quoteLogField(r.Referer()),
+		quoteLogField(r.UserAgent()),
+	)
+}
 
+// quoteLogField는 따옴표와 제어 문자를 이스케이프하여 큰따옴표로 감싼 값 반환 (비어 있으면 "-")
+func quoteLogField(value string) string {
+	if value == "" {
+		return `"-"`
+	}
+	return strconv.Quote(value)
+}
@@ -0,0 +1,169 @@
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultSampleMaxBody는 LOG_SAMPLE_MAX_BODY 기본값 (바이트)
+const DefaultSampleMaxBody = 4096
+
+// redactedValue는 샘플 상세 로그에서 비밀 값을 대신해 기록하는 문자열
+const redactedValue = "[REDACTED]"
+
+// sensitiveHeaders는 샘플 상세 로그에서 항상 값을 가리는 헤더
+var sensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Admin-Token"}
+
+// secretFieldPattern은 JSON/폼 바디에서 비밀 값으로 보는 필드 (password, token, api_key 등)와 그 값
+var secretFieldPattern = regexp.MustCompile(`(?i)("?[\w-]*(?:password|passwd|secret|token|api[_-]?key|authorization)[\w-]*"?\s*[:=]\s*)("(?:[^"\\]|\\.)*"|[^&\s,}]+)`)
+
+// logSampler는 일부 요청만 헤더/바디를 포함한 상세 로그를 남기는 설정 (LOG_SAMPLE_RATE)
+type logSampler struct {
+	rate          float64
+	maxBody       int                 // 요청/응답 바디별 최대 기록 크기 (바이트)
+	secretHeaders map[string]bool     // 값을 가릴 헤더 (정규화된 이름)
+	redact        func(string) string // 바디에 추가로 적용할 마스킹 (쿼리 개인정보 등, nil이면 생략)
+}
+
+// SetSampling은 rate 비율(0~1)의 요청만 헤더와 바디를 포함한 상세 로그를 남기도록 설정
+// 나머지 요청은 평소처럼 메타데이터 한 줄만 기록한다. 0이면 샘플링하지 않음.
+// secretHeaders(와 Authorization/Cookie 등)의 값과 바디의 비밀 필드는 가려서 기록하며, redact가 있으면 바디에 추가로 적용
+func (al *AccessLogger) SetSampling(rate float64, maxBody int, secretHeaders []string, redact func(string) string) error {
+	if rate < 0 || rate > 1 {
+		return fmt.Errorf("invalid log sample rate %g (0.0-1.0)", rate)
+	}
+	if rate == 0 {
+		al.sampler = nil
+		return nil
+	}
+	if maxBody <= 0 {
+		maxBody = DefaultSampleMaxBody
+	}
+
+	sampler := &logSampler{
+		rate:          rate,
+		maxBody:       maxBody,
+		secretHeaders: make(map[string]bool),
+		redact:        redact,
+	}
+	for _, names := range [][]string{sensitiveHeaders, secretHeaders} {
+		for _, name := range names {
+			if name != "" {
+				sampler.secretHeaders[http.CanonicalHeaderKey(name)] = true
+			}
+		}
+	}
+	al.sampler = sampler
+	return nil
+}
+
+// sampled는 요청마다 무작위로 상세 로그 대상인지 결정 (샘플링이 꺼져 있으면 false)
+func (ls *logSampler) sampled() bool {
+	return ls != nil && rand.Float64() < ls.rate
+}
+
+// cappedBuffer는 최대 limit 바이트까지만 보관하는 버퍼 (넘친 바이트 수는 truncated에 누적)
+type cappedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated int64
+}
+
+// Write는 넘친 바이트도 쓴 것으로 보고 len(b) 반환 (io.TeeReader가 에러로 처리하지 않도록)
+func (cb *cappedBuffer) Write(b []byte) (int, error) {
+	keep := min(max(cb.limit-cb.buf.Len(), 0), len(b))
+	cb.buf.Write(b[:keep])
+	cb.truncated += int64(len(b) - keep)
+	return len(b), nil
+}
+
+// String은 보관한 바디 (잘린 경우 잘린 바이트 수 표시)
+func (cb *cappedBuffer) String() string {
+	if cb.truncated > 0 {
+		return fmt.Sprintf("%s...(+%d bytes)", cb.buf.String(), cb.truncated)
+	}
+	return cb.buf.String()
+}
+
+// sampledRequest는 상세 로그 대상 요청에서 캡처한 요청/응답 바디
+type sampledRequest struct {
+	reqHeader http.Header
+	reqBody   *cappedBuffer
+	respBody  *cappedBuffer
+}
+
+// start는 요청 바디를 읽히는 대로 복사하도록 감싸고 응답 바디 캡처를 시작 (스트리밍에 영향 없음)
+func (ls *logSampler) start(r *http.Request, rw *responseWriter) *sampledRequest {
+	sample := &sampledRequest{
+		reqHeader: r.Header.Clone(),
+		reqBody:   &cappedBuffer{limit: ls.maxBody},
+		respBody:  &cappedBuffer{limit: ls.maxBody},
+	}
+	if r.Body != nil && r.Body != http.NoBody {
+		r.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.TeeReader(r.Body, sample.reqBody), r.Body}
+	}
+	rw.capture = sample.respBody
+	return sample
+}
+
+// line은 비밀 값을 가린 상세 로그 한 줄 생성
+func (ls *logSampler) line(r *http.Request, rw *responseWriter, sample *sampledRequest) string {
+	return fmt.Sprintf("🔬 [샘플] [%s] %s - %d req_headers=%s req_body=%s resp_headers=%s resp_body=%s",
+		r.Method,
+		r.URL.Path,
+		rw.statusCode,
+		strconv.Quote(ls.headers(sample.reqHeader)),
+		strconv.Quote(ls.body(sample.reqBody.String())),
+		strconv.Quote(ls.headers(rw.Header())),
+		strconv.Quote(ls.body(sample.respBody.String())),
+	)
+}
+
+// headers는 헤더를 이름순 "Name: value" 목록으로 변환 (비밀 헤더 값은 가림)
+func (ls *logSampler) headers(header http.Header) string {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		value := strings.Join(header[name], ", ")
+		if ls.secretHeaders[http.CanonicalHeaderKey(name)] {
+			value = redactedValue
+		}
+		parts = append(parts, name+": "+value)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// body는 바디의 비밀 필드 값을 가리고 추가 마스킹 적용
+func (ls *logSampler) body(body string) string {
+	body = secretFieldPattern.ReplaceAllString(body, "${1}\""+redactedValue+"\"")
+	if ls.redact != nil {
+		body = ls.redact(body)
+	}
+	return body
+}
+
+// logSample은 상세 로그 기록 (combined 형식이면 수집 도구가 파싱하는 접근 로그 대신 애플리케이션 로그에 기록)
+func (al *AccessLogger) logSample(r *http.Request, rw *responseWriter, sample *sampledRequest) {
+	line := al.sampler.line(r, rw, sample)
+	if al.format == LogFormatCombined {
+		log.Println(line)
+		return
+	}
+	al.logger.Println(line)
+}
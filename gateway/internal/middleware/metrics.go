@@ -0,0 +1,227 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultLatencyBuckets는 LATENCY_BUCKETS 기본값 (초)
+// 캐시 히트(수 ms)부터 Backend 생성(수십 초)까지 포함
+var DefaultLatencyBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// ParseLatencyBuckets는 LATENCY_BUCKETS 값(초, 쉼표 구분)을 검증하여 버킷 경계로 변환
+// 비어 있으면 DefaultLatencyBuckets, 경계는 양수이고 중복 없이 정렬된다.
+func ParseLatencyBuckets(values []string) ([]float64, error) {
+	if len(values) == 0 {
+		return DefaultLatencyBuckets, nil
+	}
+
+	buckets := make([]float64, 0, len(values))
+	for _, value := range values {
+		bound, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || bound <= 0 || math.IsInf(bound, 0) || math.IsNaN(bound) {
+			return nil, fmt.Errorf("invalid latency bucket: %q", value)
+		}
+		buckets = append(buckets, bound)
+	}
+	sort.Float64s(buckets)
+	for i := 1; i < len(buckets); i++ {
+		if buckets[i] == buckets[i-1] {
+			return nil, fmt.Errorf("duplicate latency bucket: %g", buckets[i])
+		}
+	}
+	return buckets, nil
+}
+
+// 요청 처리 시간 히스토그램의 캐시 결과 라벨 (cache="...")
+// 캐시 히트(수 ms)와 Backend 호출(수 초)을 나눠 보도록 핸들러가 SetCacheOutcome으로 기록한 X-Cache 값을 사용
+const (
+	CacheLabelHit    = "hit"
+	CacheLabelMiss   = "miss"
+	CacheLabelStale  = "stale"  // Backend 장애로 만료된 캐시 응답
+	CacheLabelBypass = "bypass" // 캐시를 사용할 수 없어 Backend로 바로 전달
+	CacheLabelNone   = "none"   // 캐시 대상이 아닌 요청 (헬스 체크, 관리자, 일반 프록시 등)
+)
+
+// cacheLabels는 출력 순서를 고정한 캐시 결과 라벨 목록
+var cacheLabels = []string{CacheLabelHit, CacheLabelMiss, CacheLabelStale, CacheLabelBypass, CacheLabelNone}
+
+// cacheOutcomeContextKey는 요청 컨텍스트에 캐시 결과를 저장하기 위한 키
+type cacheOutcomeContextKey struct{}
+
+// SetCacheOutcome은 현재 요청의 캐시 결과(X-Cache 값: HIT/MISS/STALE/BYPASS)를 처리 시간 지표에 기록
+// LatencyHistogram 미들웨어를 거치지 않은 요청이면 아무 것도 하지 않음
+func SetCacheOutcome(ctx context.Context, status string) {
+	if outcome, ok := ctx.Value(cacheOutcomeContextKey{}).(*atomic.Value); ok {
+		outcome.Store(strings.ToLower(status))
+	}
+}
+
+// cacheLabel은 기록된 캐시 결과를 라벨로 변환 (기록이 없거나 알 수 없는 값이면 none)
+func cacheLabel(outcome *atomic.Value) string {
+	label, _ := outcome.Load().(string)
+	switch label {
+	case CacheLabelHit, CacheLabelMiss, CacheLabelStale, CacheLabelBypass:
+		return label
+	}
+	return CacheLabelNone
+}
+
+// histSeries는 라벨 하나의 버킷별 관측 수와 합계
+type histSeries struct {
+	counts []atomic.Int64 // 버킷별 관측 수 (마지막은 +Inf)
+	count  atomic.Int64
+	sumNs  atomic.Int64
+}
+
+// LatencyHistogram은 요청 처리 시간 히스토그램 (Prometheus histogram과 같은 누적 버킷)
+// 캐시 결과 라벨마다 별도 시리즈로 집계한다.
+type LatencyHistogram struct {
+	bounds []float64              // 버킷 상한 (초, 오름차순)
+	series map[string]*histSeries // 캐시 결과 라벨별 (cacheLabels)
+}
+
+// NewLatencyHistogram은 주어진 버킷 경계(초, 오름차순)로 히스토그램 생성
+func NewLatencyHistogram(bounds []float64) *LatencyHistogram {
+	lh := &LatencyHistogram{
+		bounds: bounds,
+		series: make(map[string]*histSeries, len(cacheLabels)),
+	}
+	for _, label := range cacheLabels {
+		lh.series[label] = &histSeries{counts: make([]atomic.Int64, len(bounds)+1)}
+	}
+	return lh
+}
+
+// Observe는 캐시 결과 라벨 없이(none) 처리 시간 하나를 기록
+func (lh *LatencyHistogram) Observe(d time.Duration) {
+	lh.observe(CacheLabelNone, d)
+}
+
+// observe는 캐시 결과 라벨의 시리즈에 처리 시간 하나를 기록
+func (lh *LatencyHistogram) observe(label string, d time.Duration) {
+	s := lh.series[label]
+	i := sort.SearchFloat64s(lh.bounds, d.Seconds())
+	s.counts[i].Add(1)
+	s.count.Add(1)
+	s.sumNs.Add(d.Nanoseconds())
+}
+
+// Middleware는 요청 처리 시간을 캐시 결과 라벨과 함께 히스토그램에 기록하는 미들웨어
+func (lh *LatencyHistogram) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		outcome := &atomic.Value{}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), cacheOutcomeContextKey{}, outcome)))
+		lh.observe(cacheLabel(outcome), time.Since(start))
+	})
+}
+
+// cumulative는 주어진 라벨 시리즈를 합친 버킷별 누적 관측 수 (마지막은 전체 수)
+func (lh *LatencyHistogram) cumulative(labels ...string) []int64 {
+	result := make([]int64, len(lh.bounds)+1)
+	var total int64
+	for i := range result {
+		for _, label := range labels {
+			total += lh.series[label].counts[i].Load()
+		}
+		result[i] = total
+	}
+	return result
+}
+
+// Percentile은 버킷 안에서 선형 보간한 q 분위수 추정값 (초, 관측이 없으면 0)
+// 마지막 경계를 넘는 관측은 마지막 경계 값으로 취급
+func (lh *LatencyHistogram) Percentile(q float64) float64 {
+	return lh.percentile(q, lh.cumulative(cacheLabels...))
+}
+
+// percentile은 누적 관측 수에서 q 분위수 추정
+func (lh *LatencyHistogram) percentile(q float64, cum []int64) float64 {
+	total := cum[len(cum)-1]
+	if total == 0 {
+		return 0
+	}
+
+	rank := q * float64(total)
+	for i, c := range cum {
+		if float64(c) < rank {
+			continue
+		}
+		if i == len(lh.bounds) {
+			return lh.bounds[len(lh.bounds)-1]
+		}
+		lower, prev := 0.0, int64(0)
+		if i > 0 {
+			lower, prev = lh.bounds[i-1], cum[i-1]
+		}
+		inBucket := c - prev
+		if inBucket == 0 {
+			return lh.bounds[i]
+		}
+		return lower + (lh.bounds[i]-lower)*(rank-float64(prev))/float64(inBucket)
+	}
+	return lh.bounds[len(lh.bounds)-1]
+}
+
+// summary는 주어진 라벨 시리즈를 합친 요약 (요청 수, 평균, p50/p90/p99, 초 단위)
+func (lh *LatencyHistogram) summary(labels ...string) map[string]any {
+	var count, sumNs int64
+	for _, label := range labels {
+		count += lh.series[label].count.Load()
+		sumNs += lh.series[label].sumNs.Load()
+	}
+	avg := 0.0
+	if count > 0 {
+		avg = float64(sumNs) / float64(count) / float64(time.Second)
+	}
+	cum := lh.cumulative(labels...)
+	return map[string]any{
+		"count":       count,
+		"avg_seconds": avg,
+		"p50_seconds": lh.percentile(0.5, cum),
+		"p90_seconds": lh.percentile(0.9, cum),
+		"p99_seconds": lh.percentile(0.99, cum),
+	}
+}
+
+// Summary는 진단 리포트용 요약 (전체와 캐시 결과 라벨별 요청 수, 평균, p50/p90/p99, 초 단위)
+func (lh *LatencyHistogram) Summary() map[string]any {
+	result := lh.summary(cacheLabels...)
+	result["buckets"] = lh.bounds
+
+	byCache := make(map[string]any)
+	for _, label := range cacheLabels {
+		if lh.series[label].count.Load() > 0 {
+			byCache[label] = lh.summary(label)
+		}
+	}
+	result["by_cache"] = byCache
+	return result
+}
+
+// WritePrometheus는 Prometheus 텍스트 형식으로 캐시 결과 라벨별 히스토그램 출력
+func (lh *LatencyHistogram) WritePrometheus(w io.Writer) {
+	const name = "gateway_request_duration_seconds"
+
+	fmt.Fprintf(w, "# HELP %s Request latency in seconds.\n", name)
+	fmt.Fprintf(w, "# TYPE %s histogram\n", name)
+	for _, label := range cacheLabels {
+		s := lh.series[label]
+		cum := lh.cumulative(label)
+		for i, bound := range lh.bounds {
+			fmt.Fprintf(w, "%s_bucket{cache=\"%s\",le=\"%s\"} %d\n", name, label, strconv.FormatFloat(bound, 'g', -1, 64), cum[i])
+		}
+		fmt.Fprintf(w, "%s_bucket{cache=\"%s\",le=\"+Inf\"} %d\n", name, label, cum[len(cum)-1])
+		fmt.Fprintf(w, "%s_sum{cache=\"%s\"} %s\n", name, label, strconv.FormatFloat(float64(s.sumNs.Load())/float64(time.Second), 'g', -1, 64))
+		fmt.Fprintf(w, "%s_count{cache=\"%s\"} %d\n", name, label, cum[len(cum)-1])
+	}
+}
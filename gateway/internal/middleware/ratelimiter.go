@@ -1,88 +1,477 @@
 package middleware
 
 import (
+	"container/list"
+	"context"
+	"fmt"
 	"log"
+	"math"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"golang.org/x/time/rate"
 )
 
-// RateLimiter는 IP 기반 Rate Limiting을 구현
+// DefaultMaxClients는 RATE_LIMIT_MAX_CLIENTS 기본값 (추적하는 클라이언트 Limiter 수 상한)
+const DefaultMaxClients = 10000
+
+// RateLimiter는 클라이언트 식별자(API 키 또는 IP) 기반 Rate Limiting을 구현
+// 클라이언트별 Limiter는 LRU로 관리하여 capacity를 넘으면 가장 오래 요청하지 않은 클라이언트부터 제거
 type RateLimiter struct {
-	limiters map[string]*rate.Limiter
-	mu       sync.RWMutex
-	rate     rate.Limit
+	limiters map[string]*list.Element
+	order    *list.List // 앞쪽이 최근 요청 (값은 *limiterEntry)
+	capacity int        // 0이면 무제한
+	evicted  atomic.Int64
+	mu       sync.Mutex
+	rate     rate.Limit // 익명(IP) 클라이언트
 	burst    int
+
+	authRate  rate.Limit // 인증된(API 키) 클라이언트
+	authBurst int
+
+	// 특정 IP의 익명 한도 재정의 (IP_BURST_OVERRIDES, 시작 시 한 번 설정)
+	ipOverrides map[string]limitOverride
+
+	// 익명 클라이언트 IP 결정 (TRUSTED_PROXIES 뒤에서만 X-Forwarded-For 반영, SetClientIPResolver)
+	clientIPs *ClientIPResolver
+
+	// 배포 직후 재접속 폭주 완화: 시작 후 warmup 동안 버스트를 multiplier배에서 1배로 선형 감소
+	startedAt        time.Time
+	warmup           time.Duration
+	warmupMultiplier float64
+
+	// 한도 접근 경고: 남은 토큰이 burst의 warnFraction 미만이면 클라이언트당 warnInterval마다 한 번 로그
+	warnFraction float64
+	warnInterval time.Duration
+	lastWarned   sync.Map // key → time.Time
+	nearLimit    atomic.Int64
+
+	// 한도 초과 요청도 캐시 히트면 응답 (SetCacheFallback)
+	cacheFallback bool
+
+	// 모든 응답에 X-RateLimit-* 헤더 추가 (SetQuotaHeaders)
+	quotaHeaders bool
+
+	// 대기 모드: 0보다 크면 즉시 거부하는 대신 최대 maxWait까지 토큰을 기다림 (SetWaitMode)
+	maxWait time.Duration
+
+	// 적응형 모드: 설정 시 초당 요청 수에 Backend 상태 계수(0~1)를 곱해 적용 (SetHealthSource)
+	health HealthSource
+
+	// 인증 미들웨어보다 바깥에 있을 때 요청의 API 키를 확인하는 함수 (SetIdentifier)
+	identify func(*http.Request) Identity
+}
+
+// limiterEntry는 LRU 항목 (클라이언트 키, Limiter, 진단용 마지막 요청 시각)
+type limiterEntry struct {
+	key      string
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// HealthSource는 적응형 Rate Limiting에 사용할 Backend 상태 계수 제공자
+// 1이면 설정된 한도 그대로, 1보다 작으면 그 비율만큼 초당 요청 수를 줄임
+type HealthSource interface {
+	HealthFactor() float64
 }
 
 // NewRateLimiter는 새로운 Rate Limiter 생성
-// r: 초당 허용 요청 수
-// b: 버스트 허용량
+// r: 초당 허용 요청 수 (0 이하면 무제한)
+// b: 버스트 허용량 (양수 한도에서 0 이하면 1)
+// 인증된 클라이언트도 SetAuthenticatedTier 호출 전까지는 같은 한도를 사용
 func NewRateLimiter(r float64, b int) *RateLimiter {
+	limit, burst := validateLimit("익명", r, b)
 	return &RateLimiter{
-		limiters: make(map[string]*rate.Limiter),
-		rate:     rate.Limit(r),
-		burst:    b,
+		limiters:  make(map[string]*list.Element),
+		order:     list.New(),
+		capacity:  DefaultMaxClients,
+		rate:      limit,
+		burst:     burst,
+		authRate:  limit,
+		authBurst: burst,
+		clientIPs: NewClientIPResolver(nil),
+		startedAt: time.Now(),
+	}
+}
+
+// SetClientIPResolver는 익명 클라이언트 IP를 결정할 Resolver 설정 (트래픽 처리 시작 전에 호출)
+// 설정하지 않으면 X-Forwarded-For를 무시하고 직접 연결한 주소를 사용
+func (rl *RateLimiter) SetClientIPResolver(resolver *ClientIPResolver) {
+	rl.clientIPs = resolver
+}
+
+// SetMaxClients는 추적할 클라이언트 Limiter 수 상한 설정 (0 이하면 무제한, 트래픽 처리 시작 전에 호출)
+func (rl *RateLimiter) SetMaxClients(n int) {
+	rl.capacity = max(n, 0)
+}
+
+// SetIdentifier는 컨텍스트에 Identity가 없을 때 요청에서 직접 식별 정보를 확인하는 함수 설정
+// Rate Limiter를 Auth 바깥에 두어 잘못된 키로 보내는 요청도 IP 한도를 받게 할 때 사용 (유효한 키만 키 등급)
+func (rl *RateLimiter) SetIdentifier(identify func(*http.Request) Identity) {
+	rl.identify = identify
+}
+
+// SetAuthenticatedTier는 인증된 클라이언트에 적용할 한도 설정 (r이 0 이하면 무제한)
+func (rl *RateLimiter) SetAuthenticatedTier(r float64, b int) {
+	rl.authRate, rl.authBurst = validateLimit("인증", r, b)
+}
+
+// validateLimit은 설정된 한도를 검증
+// 0 이하의 rate는 rate.NewLimiter(0, b)처럼 모든 요청을 막는 대신 무제한(rate.Inf)으로 취급하고,
+// 양수 rate에 0 이하의 burst는 요청이 하나도 통과하지 못하므로 1로 보정
+func validateLimit(tier string, r float64, b int) (rate.Limit, int) {
+	if r <= 0 {
+		log.Printf("⚠️ %s 클라이언트 Rate Limit이 %g: 무제한으로 처리 (Rate Limiting 비활성화)", tier, r)
+		return rate.Inf, b
+	}
+	if b <= 0 {
+		log.Printf("⚠️ %s 클라이언트 버스트가 %d: 1로 보정", tier, b)
+		b = 1
+	}
+	return rate.Limit(r), b
+}
+
+// limitOverride는 IP별로 재정의한 한도
+type limitOverride struct {
+	rate  rate.Limit
+	burst int
+}
+
+// SetIPOverrides는 특정 IP에 기본값과 다른 버스트(선택적으로 초당 요청 수)를 적용
+// 항목 형식은 "IP=버스트" 또는 "IP=버스트:초당요청수" (예: "10.0.0.5=100", "10.0.0.6=50:20")
+// 초당 요청 수를 생략하면 익명 클라이언트 기본값 사용
+func (rl *RateLimiter) SetIPOverrides(entries []string) error {
+	overrides := make(map[string]limitOverride, len(entries))
+	for _, entry := range entries {
+		ip, value, ok := strings.Cut(entry, "=")
+		ip = strings.TrimSpace(ip)
+		if !ok || net.ParseIP(ip) == nil {
+			return fmt.Errorf("invalid override %q: expected IP=burst[:rate]", entry)
+		}
+
+		burstValue, rateValue, hasRate := strings.Cut(value, ":")
+		burst, err := strconv.Atoi(strings.TrimSpace(burstValue))
+		if err != nil || burst <= 0 {
+			return fmt.Errorf("invalid burst in %q", entry)
+		}
+
+		override := limitOverride{rate: rl.rate, burst: burst}
+		if hasRate {
+			r, err := strconv.ParseFloat(strings.TrimSpace(rateValue), 64)
+			if err != nil || r <= 0 {
+				return fmt.Errorf("invalid rate in %q", entry)
+			}
+			override.rate = rate.Limit(r)
+		}
+		overrides[net.ParseIP(ip).String()] = override
+	}
+	rl.ipOverrides = overrides
+	return nil
+}
+
+// ipOverride는 클라이언트 IP(clientIP 결과)에 대한 재정의 한도 조회
+func (rl *RateLimiter) ipOverride(ip string) (limitOverride, bool) {
+	override, ok := rl.ipOverrides[ip]
+	return override, ok
+}
+
+// clientIP는 익명 클라이언트의 Limiter 키와 재정의 조회에 사용할 IP (포트 제외)
+// 신뢰하는 프록시를 거친 요청만 X-Forwarded-For를 반영하므로 헤더 위조로 재정의 한도나 새 버킷을 얻을 수 없다.
+func (rl *RateLimiter) clientIP(r *http.Request) string {
+	if ip := rl.clientIPs.ClientIP(r); ip != nil {
+		return ip.String()
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// SetWarmup은 서버 시작 후 버스트를 완화하는 기간 설정
+// multiplier배의 버스트에서 시작해 warmup 동안 설정값까지 선형으로 감소
+func (rl *RateLimiter) SetWarmup(warmup time.Duration, multiplier float64) {
+	rl.warmup = warmup
+	rl.warmupMultiplier = multiplier
+}
+
+// SetNearLimitWarning은 한도 접근 경고 기준 설정 (fraction이 0이면 비활성화)
+// 같은 클라이언트에 대한 경고는 interval마다 최대 한 번
+func (rl *RateLimiter) SetNearLimitWarning(fraction float64, interval time.Duration) {
+	rl.warnFraction = fraction
+	rl.warnInterval = interval
+}
+
+// NearLimitWarnings는 지금까지 발생한 한도 접근 경고 수 반환
+func (rl *RateLimiter) NearLimitWarnings() int64 {
+	return rl.nearLimit.Load()
+}
+
+// checkNearLimit은 허용된 요청 이후 남은 토큰이 기준 미만이면 경고
+func (rl *RateLimiter) checkNearLimit(key string, limiter *rate.Limiter) {
+	if rl.warnFraction <= 0 {
+		return
 	}
+	if limiter.Tokens() >= rl.warnFraction*float64(limiter.Burst()) {
+		return
+	}
+
+	now := time.Now()
+	if last, ok := rl.lastWarned.Load(key); ok && now.Sub(last.(time.Time)) < rl.warnInterval {
+		return
+	}
+	rl.lastWarned.Store(key, now)
+
+	rl.nearLimit.Add(1)
+	log.Printf("📈 클라이언트 한도 접근: %s (남은 토큰 %.1f / %d)", key, limiter.Tokens(), limiter.Burst())
 }
 
-// getLimiter는 IP별 Limiter 반환 (없으면 생성)
-func (rl *RateLimiter) getLimiter(ip string) *rate.Limiter {
-	rl.mu.RLock()
-	limiter, exists := rl.limiters[ip]
-	rl.mu.RUnlock()
+// SetHealthSource는 적응형 모드 설정: Backend 상태가 나쁘면 한도를 줄이고 회복되면 되돌림
+func (rl *RateLimiter) SetHealthSource(src HealthSource) {
+	rl.health = src
+}
+
+// effectiveRate는 Backend 상태 계수를 반영한 초당 요청 수 반환 (적응형 모드가 아니면 그대로)
+func (rl *RateLimiter) effectiveRate(r rate.Limit) rate.Limit {
+	if rl.health == nil {
+		return r
+	}
+	factor := min(max(rl.health.HealthFactor(), 0), 1)
+	return r * rate.Limit(factor)
+}
 
-	if exists {
-		return limiter
+// effectiveBurst는 warmup 경과 시간에 따라 조정된 버스트 반환
+func (rl *RateLimiter) effectiveBurst(b int) int {
+	elapsed := time.Since(rl.startedAt)
+	if rl.warmup <= 0 || rl.warmupMultiplier <= 1 || elapsed >= rl.warmup {
+		return b
 	}
 
+	remaining := 1 - float64(elapsed)/float64(rl.warmup)
+	return int(float64(b) * (1 + (rl.warmupMultiplier-1)*remaining))
+}
+
+// getLimiter는 클라이언트별 Limiter를 반환하고 최근 요청으로 표시 (없으면 주어진 한도로 생성)
+// warmup 중에는 기존 Limiter의 버스트를, 적응형 모드에서는 초당 요청 수도 현재 시점 값으로 갱신
+// 새 클라이언트로 capacity를 넘으면 가장 오래 요청하지 않은 클라이언트의 Limiter를 제거
+func (rl *RateLimiter) getLimiter(key string, r rate.Limit, b int) *rate.Limiter {
+	r = rl.effectiveRate(r)
+	b = rl.effectiveBurst(b)
+	now := time.Now()
+
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
-	// Double-check
-	if limiter, exists = rl.limiters[ip]; exists {
-		return limiter
+	if elem, ok := rl.limiters[key]; ok {
+		entry := elem.Value.(*limiterEntry)
+		entry.lastSeen = now
+		rl.order.MoveToFront(elem)
+		if entry.limiter.Burst() != b {
+			entry.limiter.SetBurst(b)
+		}
+		if entry.limiter.Limit() != r {
+			entry.limiter.SetLimit(r)
+		}
+		return entry.limiter
 	}
 
-	limiter = rate.NewLimiter(rl.rate, rl.burst)
-	rl.limiters[ip] = limiter
+	limiter := rate.NewLimiter(r, b)
+	rl.limiters[key] = rl.order.PushFront(&limiterEntry{key: key, limiter: limiter, lastSeen: now})
+	for rl.capacity > 0 && rl.order.Len() > rl.capacity {
+		oldest := rl.order.Back()
+		rl.order.Remove(oldest)
+		evictedKey := oldest.Value.(*limiterEntry).key
+		delete(rl.limiters, evictedKey)
+		rl.lastWarned.Delete(evictedKey)
+		rl.evicted.Add(1)
+	}
 
 	return limiter
 }
 
-// Middleware는 Rate Limiting 미들웨어
-func (rl *RateLimiter) Middleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// 클라이언트 IP 추출
-		ip := r.RemoteAddr
-		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
-			ip = forwarded
-		}
+// Len은 현재 추적 중인 클라이언트(IP) 수 반환
+func (rl *RateLimiter) Len() int {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	return rl.order.Len()
+}
 
-		limiter := rl.getLimiter(ip)
+// ClientLimitState는 클라이언트 하나의 현재 Rate Limit 상태
+type ClientLimitState struct {
+	Key      string    `json:"key"` // "ip:..." 또는 "key:..."
+	Tokens   float64   `json:"tokens"`
+	Burst    int       `json:"burst"`
+	Limited  bool      `json:"limited"` // 지금 요청하면 429
+	LastSeen time.Time `json:"last_seen"`
+}
 
-		if !limiter.Allow() {
-			log.Printf("⚠️ Rate Limit 초과: %s", ip)
-			http.Error(w, `{"error": "Too Many Requests", "message": "요청 한도를 초과했습니다. 잠시 후 다시 시도해주세요."}`, http.StatusTooManyRequests)
-			return
-		}
+// RateLimitSnapshot은 Rate Limiter 상태 요약 (최근 요청한 클라이언트 최대 N개)
+type RateLimitSnapshot struct {
+	Tracked  int                `json:"tracked"`
+	Capacity int                `json:"capacity"` // 0이면 무제한
+	Evicted  int64              `json:"evicted"`  // 상한 초과로 제거된 클라이언트 누적 수
+	Recent   []ClientLimitState `json:"recent"`
+}
 
-		next.ServeHTTP(w, r)
-	})
+// state는 LRU 항목의 현재 상태 반환
+func (e *limiterEntry) state() ClientLimitState {
+	tokens := e.limiter.Tokens()
+	return ClientLimitState{
+		Key:      e.key,
+		Tokens:   tokens,
+		Burst:    e.limiter.Burst(),
+		Limited:  tokens < 1,
+		LastSeen: e.lastSeen,
+	}
+}
+
+// ClientState는 클라이언트 키("ip:..." 또는 "key:...")의 현재 상태 조회 (추적 중이 아니면 false)
+// 조회만 하므로 LRU 순서는 바뀌지 않음
+func (rl *RateLimiter) ClientState(key string) (ClientLimitState, bool) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	elem, ok := rl.limiters[key]
+	if !ok {
+		return ClientLimitState{}, false
+	}
+	return elem.Value.(*limiterEntry).state(), true
 }
 
-// CleanupOldLimiters는 오래된 Limiter 정리 (메모리 관리용)
-func (rl *RateLimiter) CleanupOldLimiters() {
+// Snapshot은 추적 중인 클라이언트 수와 최근 요청한 순서로 최대 limit개의 상태 반환
+func (rl *RateLimiter) Snapshot(limit int) RateLimitSnapshot {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
-	// 간단한 구현: 일정 수 이상이면 전체 초기화
-	if len(rl.limiters) > 10000 {
-		rl.limiters = make(map[string]*rate.Limiter)
-		log.Println("🧹 Rate Limiter 캐시 정리")
+	states := make([]ClientLimitState, 0, min(limit, rl.order.Len()))
+	for elem := rl.order.Front(); elem != nil && len(states) < limit; elem = elem.Next() {
+		states = append(states, elem.Value.(*limiterEntry).state())
+	}
+
+	return RateLimitSnapshot{
+		Tracked:  rl.order.Len(),
+		Capacity: rl.capacity,
+		Evicted:  rl.evicted.Load(),
+		Recent:   states,
 	}
 }
 
+// rateLimitedContextKey는 한도를 초과했지만 캐시 fallback을 위해 통과시킨 요청 표시
+type rateLimitedContextKey struct{}
+
+// RateLimitedFromContext는 한도를 초과한 요청인지 확인 (SetCacheFallback 활성화 시)
+func RateLimitedFromContext(ctx context.Context) bool {
+	limited, _ := ctx.Value(rateLimitedContextKey{}).(bool)
+	return limited
+}
+
+// SetWaitMode는 토큰이 없을 때 바로 429를 반환하지 않고 최대 maxWait까지 기다리도록 설정
+// 기다려도 maxWait 안에 토큰을 얻을 수 없으면 대기 없이 바로 거부 (0이면 즉시 거부)
+func (rl *RateLimiter) SetWaitMode(maxWait time.Duration) {
+	rl.maxWait = maxWait
+}
+
+// allow는 요청을 허용할지 결정 (대기 모드면 토큰을 기다림)
+func (rl *RateLimiter) allow(ctx context.Context, limiter *rate.Limiter) bool {
+	if rl.maxWait <= 0 {
+		return limiter.Allow()
+	}
 
+	// Wait는 데드라인 안에 토큰을 얻을 수 없으면 기다리지 않고 바로 에러 반환
+	ctx, cancel := context.WithTimeout(ctx, rl.maxWait)
+	defer cancel()
+	return limiter.Wait(ctx) == nil
+}
 
+// SetCacheFallback은 한도 초과 시 바로 429를 반환하지 않고 핸들러에 넘겨
+// 캐시된 응답이 있으면 그것으로 응답하도록 설정
+func (rl *RateLimiter) SetCacheFallback(enabled bool) {
+	rl.cacheFallback = enabled
+}
+
+// SetQuotaHeaders는 Rate Limiter를 거치는 모든 응답에 남은 한도 헤더를 추가하도록 설정
+// (X-RateLimit-Limit, X-RateLimit-Remaining, X-RateLimit-Reset, 거부 시 Retry-After)
+func (rl *RateLimiter) SetQuotaHeaders(enabled bool) {
+	rl.quotaHeaders = enabled
+}
+
+// writeQuotaHeaders는 클라이언트 Limiter 상태로 한도 헤더 설정
+// Limit은 버스트(최대 토큰 수), Remaining은 남은 토큰 수(내림), Reset은 토큰이 가득 찰 때까지 남은 초(올림)
+func writeQuotaHeaders(w http.ResponseWriter, limiter *rate.Limiter, limited bool) {
+	now := time.Now()
+	tokens := limiter.TokensAt(now)
+	burst := limiter.Burst()
+	perSecond := float64(limiter.Limit())
+
+	reset := 0.0
+	if perSecond > 0 && tokens < float64(burst) {
+		reset = math.Ceil((float64(burst) - tokens) / perSecond)
+	}
+
+	h := w.Header()
+	h.Set("X-RateLimit-Limit", strconv.Itoa(burst))
+	h.Set("X-RateLimit-Remaining", strconv.Itoa(int(math.Max(0, math.Floor(tokens)))))
+	h.Set("X-RateLimit-Reset", strconv.Itoa(int(reset)))
+	if limited && perSecond > 0 {
+		// 토큰 하나가 다시 생길 때까지 남은 시간
+		h.Set("Retry-After", strconv.Itoa(int(math.Max(1, math.Ceil((1-tokens)/perSecond)))))
+	}
+}
+
+// WriteTooManyRequests는 Rate Limit 초과 429 응답 작성
+func WriteTooManyRequests(w http.ResponseWriter, r *http.Request) {
+	WriteError(w, r, http.StatusTooManyRequests, "요청 한도를 초과했습니다. 잠시 후 다시 시도해주세요.")
+}
+
+// Middleware는 Rate Limiting 미들웨어
+func (rl *RateLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 클라이언트 IP 추출 (TRUSTED_PROXIES 반영)
+		ip := rl.clientIP(r)
+
+		// 인증된 클라이언트는 API 키 단위로, 익명 클라이언트는 IP 단위로 제한
+		// IP_BURST_OVERRIDES에 있는 IP는 재정의한 한도 사용
+		key, limit, burst := "ip:"+ip, rl.rate, rl.burst
+		identity := IdentityFromContext(r.Context())
+		if !identity.Authenticated && rl.identify != nil {
+			identity = rl.identify(r)
+		}
+		if identity.Authenticated {
+			key, limit, burst = "key:"+identity.APIKey, rl.authRate, rl.authBurst
+		} else if override, ok := rl.ipOverride(ip); ok {
+			limit, burst = override.rate, override.burst
+		}
+
+		// 무제한 등급은 Limiter를 만들지 않고 통과
+		if limit == rate.Inf {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		limiter := rl.getLimiter(key, limit, burst)
+
+		if !rl.allow(r.Context(), limiter) {
+			log.Printf("⚠️ Rate Limit 초과: %s", ip)
+			if rl.quotaHeaders {
+				writeQuotaHeaders(w, limiter, true)
+			}
+			if rl.cacheFallback {
+				// 캐시 히트로 응답할 수 있는지 핸들러가 판단 (미스면 핸들러가 429 반환)
+				next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), rateLimitedContextKey{}, true)))
+				return
+			}
+			WriteTooManyRequests(w, r)
+			return
+		}
+		rl.checkNearLimit(key, limiter)
+		if rl.quotaHeaders {
+			writeQuotaHeaders(w, limiter, false)
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
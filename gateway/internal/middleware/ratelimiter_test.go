@@ -0,0 +1,230 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"golang.org/x/time/rate"
+)
+
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+})
+
+// sendN은 같은 IP에서 n번 요청하고 상태 코드 목록 반환
+func sendN(h http.Handler, ip string, n int) []int {
+	codes := make([]int, n)
+	for i := range codes {
+		req := httptest.NewRequest(http.MethodGet, "/api/chat", nil)
+		req.RemoteAddr = ip
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		codes[i] = rec.Code
+	}
+	return codes
+}
+
+func TestRateLimiterNonPositiveRateIsUnlimited(t *testing.T) {
+	for _, tt := range []struct {
+		rate  float64
+		burst int
+	}{{0, 0}, {0, 5}, {-1, 10}} {
+		rl := NewRateLimiter(tt.rate, tt.burst)
+		for i, code := range sendN(rl.Middleware(okHandler), "10.0.0.1", 100) {
+			if code != http.StatusOK {
+				t.Fatalf("rate=%g burst=%d: request %d got %d, want 200", tt.rate, tt.burst, i, code)
+			}
+		}
+		if n := rl.Len(); n != 0 {
+			t.Errorf("rate=%g burst=%d: tracked %d limiters, want 0 (unlimited bypasses limiter)", tt.rate, tt.burst, n)
+		}
+	}
+}
+
+func TestRateLimiterAuthenticatedTierUnlimited(t *testing.T) {
+	rl := NewRateLimiter(1, 1)
+	rl.SetAuthenticatedTier(0, 0)
+	rl.SetIdentifier(func(*http.Request) Identity { return Identity{APIKey: "k", Authenticated: true} })
+
+	for i, code := range sendN(rl.Middleware(okHandler), "10.0.0.1", 20) {
+		if code != http.StatusOK {
+			t.Fatalf("request %d got %d, want 200", i, code)
+		}
+	}
+}
+
+func TestRateLimiterPositiveLimits(t *testing.T) {
+	tests := []struct {
+		name  string
+		rate  float64
+		burst int
+		want  int // 연속 요청 중 통과하는 수
+	}{
+		{"burst 3", 0.001, 3, 3},
+		{"burst 1", 0.001, 1, 1},
+		{"zero burst corrected to 1", 0.001, 0, 1},
+		{"negative burst corrected to 1", 0.001, -5, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codes := sendN(NewRateLimiter(tt.rate, tt.burst).Middleware(okHandler), "10.0.0.1", tt.want+2)
+			for i, code := range codes {
+				want := http.StatusOK
+				if i >= tt.want {
+					want = http.StatusTooManyRequests
+				}
+				if code != want {
+					t.Errorf("request %d got %d, want %d (codes %v)", i, code, want, codes)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimiterConcurrentLRUBounded(t *testing.T) {
+	const (
+		capacity   = 16
+		goroutines = 32
+		clients    = 200
+	)
+	rl := NewRateLimiter(1000, 1000)
+	rl.SetMaxClients(capacity)
+	h := rl.Middleware(okHandler)
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < clients; i++ {
+				// 고루틴마다 순서를 달리해 같은 키의 생성/갱신/제거가 겹치도록 함
+				ip := fmt.Sprintf("10.0.0.%d", (i+g)%clients)
+				sendN(h, ip, 1)
+				if n := rl.Len(); n > capacity {
+					t.Errorf("tracked %d limiters, want at most %d", n, capacity)
+					return
+				}
+				rl.Snapshot(5)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	rl.mu.Lock()
+	mapLen, listLen := len(rl.limiters), rl.order.Len()
+	rl.mu.Unlock()
+	if mapLen != capacity || listLen != capacity {
+		t.Errorf("map=%d list=%d entries, want both %d", mapLen, listLen, capacity)
+	}
+	if evicted := rl.Snapshot(0).Evicted; evicted < clients-capacity {
+		t.Errorf("evicted = %d, want at least %d", evicted, clients-capacity)
+	}
+}
+
+func TestRateLimiterConcurrentSameKeySharesLimiter(t *testing.T) {
+	rl := NewRateLimiter(1, 1)
+	rl.SetMaxClients(4)
+
+	const goroutines = 32
+	got := make([]*rate.Limiter, goroutines)
+	var wg sync.WaitGroup
+	for i := range got {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			got[i] = rl.getLimiter("ip:10.0.0.1", rl.rate, rl.burst)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, limiter := range got {
+		if limiter != got[0] {
+			t.Fatalf("goroutine %d got a different limiter for the same key", i)
+		}
+	}
+	if n := rl.Len(); n != 1 {
+		t.Errorf("tracked %d limiters, want 1", n)
+	}
+}
+
+// sendFrom은 remoteAddr에서 X-Forwarded-For(빈 값이면 생략)와 함께 요청하고 상태 코드 반환
+func sendFrom(h http.Handler, remoteAddr, forwarded string) int {
+	req := httptest.NewRequest(http.MethodGet, "/api/chat", nil)
+	req.RemoteAddr = remoteAddr
+	if forwarded != "" {
+		req.Header.Set("X-Forwarded-For", forwarded)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+// newOverrideLimiter는 버스트 1, 10.0.0.5만 버스트 5로 재정의하고 10.0.0.1을 신뢰 프록시로 둔 Rate Limiter 생성
+func newOverrideLimiter(t *testing.T) *RateLimiter {
+	t.Helper()
+	rl := NewRateLimiter(0.001, 1)
+	if err := rl.SetIPOverrides([]string{"10.0.0.5=5"}); err != nil {
+		t.Fatal(err)
+	}
+	trusted, err := ParseCIDRs([]string{"10.0.0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rl.SetClientIPResolver(NewClientIPResolver(trusted))
+	return rl
+}
+
+func TestRateLimiterSpoofedForwardedForIgnored(t *testing.T) {
+	rl := newOverrideLimiter(t)
+	h := rl.Middleware(okHandler)
+
+	// 신뢰하지 않는 peer가 재정의 대상 IP를 사칭해도 자기 주소의 기본 한도를 받음
+	if code := sendFrom(h, "203.0.113.7:40001", "10.0.0.5"); code != http.StatusOK {
+		t.Fatalf("first request: %d, want 200", code)
+	}
+	if code := sendFrom(h, "203.0.113.7:40002", "10.0.0.5"); code != http.StatusTooManyRequests {
+		t.Errorf("spoofed override: %d, want 429", code)
+	}
+
+	// 헤더를 바꿔도 새 버킷을 얻지 못함
+	if code := sendFrom(h, "203.0.113.7:40003", "198.51.100.9"); code != http.StatusTooManyRequests {
+		t.Errorf("rotated X-Forwarded-For: %d, want 429", code)
+	}
+	if n := rl.Len(); n != 1 {
+		t.Errorf("tracked %d limiters, want 1 (host-only key, port and header ignored)", n)
+	}
+	if _, ok := rl.ClientState("ip:203.0.113.7"); !ok {
+		t.Error("limiter not keyed by host-only peer IP")
+	}
+}
+
+func TestRateLimiterOverrideThroughTrustedProxy(t *testing.T) {
+	rl := newOverrideLimiter(t)
+	h := rl.Middleware(okHandler)
+
+	// 신뢰 프록시가 전달한 체인의 클라이언트 IP로 재정의 적용
+	for i := 0; i < 5; i++ {
+		if code := sendFrom(h, "10.0.0.1:5000", "198.51.100.1, 10.0.0.5"); code != http.StatusOK {
+			t.Fatalf("request %d: %d, want 200 (override burst 5)", i, code)
+		}
+	}
+	if code := sendFrom(h, "10.0.0.1:5000", "10.0.0.5"); code != http.StatusTooManyRequests {
+		t.Errorf("request beyond override burst: %d, want 429", code)
+	}
+
+	// 직접 연결한 재정의 대상도 포트와 무관하게 같은 버킷
+	rl = newOverrideLimiter(t)
+	h = rl.Middleware(okHandler)
+	for i := 0; i < 5; i++ {
+		if code := sendFrom(h, fmt.Sprintf("10.0.0.5:%d", 6000+i), ""); code != http.StatusOK {
+			t.Fatalf("direct request %d: %d, want 200", i, code)
+		}
+	}
+	if code := sendFrom(h, "10.0.0.5:7000", ""); code != http.StatusTooManyRequests {
+		t.Errorf("direct request beyond override burst: %d, want 429", code)
+	}
+}
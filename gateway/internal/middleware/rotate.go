@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// RotatingFile은 크기 기반으로 로테이션되는 로그 파일 Writer
+// 파일이 maxSize를 넘으면 path.1, path.2 ... 로 밀어내고 새 파일을 연다.
+type RotatingFile struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64 // 바이트 단위
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+// NewRotatingFile은 새로운 RotatingFile 생성
+// maxSizeMB: 로테이션 기준 크기 (MB)
+// maxBackups: 보관할 이전 파일 개수
+func NewRotatingFile(path string, maxSizeMB, maxBackups int) (*RotatingFile, error) {
+	rf := &RotatingFile{
+		path:       path,
+		maxSize:    int64(maxSizeMB) * 1024 * 1024,
+		maxBackups: maxBackups,
+	}
+	if err := rf.open(); err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+// Write는 로그를 기록하고 필요시 로테이션 (동시 호출 안전)
+func (rf *RotatingFile) Write(p []byte) (int, error) {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if rf.maxSize > 0 && rf.size+int64(len(p)) > rf.maxSize && rf.size > 0 {
+		if err := rf.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := rf.file.Write(p)
+	rf.size += int64(n)
+	return n, err
+}
+
+// Close는 파일 닫기
+func (rf *RotatingFile) Close() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	return rf.file.Close()
+}
+
+func (rf *RotatingFile) open() error {
+	file, err := os.OpenFile(rf.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("open access log failed: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("stat access log failed: %w", err)
+	}
+
+	rf.file = file
+	rf.size = info.Size()
+	return nil
+}
+
+// rotate는 현재 파일을 백업으로 밀어내고 새 파일을 연다 (mu 보유 상태에서 호출)
+func (rf *RotatingFile) rotate() error {
+	if err := rf.file.Close(); err != nil {
+		return fmt.Errorf("close access log failed: %w", err)
+	}
+
+	if rf.maxBackups > 0 {
+		os.Remove(fmt.Sprintf("%s.%d", rf.path, rf.maxBackups))
+		for i := rf.maxBackups - 1; i >= 1; i-- {
+			os.Rename(fmt.Sprintf("%s.%d", rf.path, i), fmt.Sprintf("%s.%d", rf.path, i+1))
+		}
+		if err := os.Rename(rf.path, rf.path+".1"); err != nil {
+			return fmt.Errorf("rotate access log failed: %w", err)
+		}
+	} else if err := os.Remove(rf.path); err != nil {
+		return fmt.Errorf("rotate access log failed: %w", err)
+	}
+
+	return rf.open()
+}
@@ -0,0 +1,159 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Timeout은 스트리밍이 아닌 요청 전체에 적용하는 전역 타임아웃 미들웨어
+// 응답을 버퍼링하므로 SSE처럼 스트리밍해야 하는 경로는 bypass 접두사로 제외한다.
+// 핸들러가 Flush하면 그때까지의 응답을 클라이언트로 보내며, 이후 타임아웃은 504 없이 요청만 중단한다.
+type Timeout struct {
+	timeout time.Duration
+	bypass  []string // 타임아웃을 적용하지 않는 경로 접두사
+}
+
+// NewTimeout은 새로운 Timeout 미들웨어 생성
+func NewTimeout(timeout time.Duration, bypass []string) *Timeout {
+	return &Timeout{timeout: timeout, bypass: bypass}
+}
+
+// Middleware는 타임아웃 초과 시 504 JSON을 반환하는 미들웨어
+func (t *Timeout) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if t.timeout <= 0 || t.skip(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), t.timeout)
+		defer cancel()
+
+		tw := &timeoutWriter{w: w, header: make(http.Header)}
+		done := make(chan struct{})
+		panicChan := make(chan any, 1)
+
+		go func() {
+			defer func() {
+				if p := recover(); p != nil {
+					panicChan <- p
+				}
+			}()
+			next.ServeHTTP(tw, r.WithContext(ctx))
+			close(done)
+		}()
+
+		select {
+		case p := <-panicChan:
+			panic(p)
+
+		case <-done:
+			tw.mu.Lock()
+			defer tw.mu.Unlock()
+			tw.commit()
+
+		case <-ctx.Done():
+			tw.mu.Lock()
+			defer tw.mu.Unlock()
+			tw.timedOut = true
+
+			switch {
+			case r.Context().Err() != nil:
+				// 클라이언트가 먼저 연결을 끊음: 받을 사람이 없으므로 응답하지 않음
+				log.Printf("🔌 클라이언트 연결 종료 (499): %s %s", r.Method, r.URL.Path)
+			case tw.committed:
+				// 이미 Flush로 응답을 보내기 시작해 상태 코드를 바꿀 수 없음
+				log.Printf("⏱️ 요청 타임아웃 (응답 전송 중 중단): %s %s (%v)", r.Method, r.URL.Path, t.timeout)
+			default:
+				log.Printf("⏱️ 요청 타임아웃: %s %s (%v)", r.Method, r.URL.Path, t.timeout)
+				WriteError(w, r, http.StatusGatewayTimeout, "요청 처리 시간이 초과되었습니다.")
+			}
+		}
+	})
+}
+
+// skip은 스트리밍 요청인지 확인 (bypass 경로 또는 Accept: text/event-stream)
+func (t *Timeout) skip(r *http.Request) bool {
+	for _, prefix := range t.bypass {
+		if strings.HasPrefix(r.URL.Path, prefix) {
+			return true
+		}
+	}
+	return strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
+// timeoutWriter는 타임아웃 전까지 응답을 버퍼링하는 ResponseWriter
+// Flush 이후에는 클라이언트로 바로 쓰며, 타임아웃 이후의 쓰기는 http.ErrHandlerTimeout을 반환한다.
+type timeoutWriter struct {
+	w         http.ResponseWriter
+	mu        sync.Mutex
+	header    http.Header
+	buf       bytes.Buffer
+	code      int
+	timedOut  bool
+	committed bool // 헤더를 클라이언트로 보냄 (Flush 또는 완료)
+}
+
+// commit은 버퍼링한 헤더와 바디를 클라이언트로 전송 (mu를 잡은 상태에서 호출)
+func (tw *timeoutWriter) commit() {
+	if !tw.committed {
+		for k, values := range tw.header {
+			tw.w.Header()[k] = values
+		}
+		if tw.code == 0 {
+			tw.code = http.StatusOK
+		}
+		tw.w.WriteHeader(tw.code)
+		tw.committed = true
+	}
+	if tw.buf.Len() > 0 {
+		tw.w.Write(tw.buf.Bytes())
+		tw.buf.Reset()
+	}
+}
+
+// Flush는 지금까지의 응답을 클라이언트로 보내고 flush (타임아웃 이후에는 무시)
+func (tw *timeoutWriter) Flush() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return
+	}
+	tw.commit()
+	if flusher, ok := tw.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut || tw.code != 0 {
+		return
+	}
+	tw.code = code
+}
+
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if tw.code == 0 {
+		tw.code = http.StatusOK
+	}
+	if tw.committed {
+		return tw.w.Write(b)
+	}
+	return tw.buf.Write(b)
+}
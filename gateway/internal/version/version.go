@@ -0,0 +1,23 @@
+// Package version은 빌드 시 -ldflags로 주입되는 빌드 정보를 보관한다.
+//
+//	go build -ldflags "-X github.com/devbrain/gateway/internal/version.Version=v1.2.0 \
+//	  -X github.com/devbrain/gateway/internal/version.Commit=$(git rev-parse --short HEAD) \
+//	  -X github.com/devbrain/gateway/internal/version.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)" \
+//	  -o bin/gateway cmd/server/main.go
+package version
+
+// 빌드 정보 (주입하지 않으면 기본값 유지)
+var (
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildTime = "unknown"
+)
+
+// Info는 빌드 정보를 JSON 응답용 맵으로 반환
+func Info() map[string]string {
+	return map[string]string{
+		"version":    Version,
+		"commit":     Commit,
+		"build_time": BuildTime,
+	}
+}